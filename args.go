@@ -2,6 +2,9 @@ package argparse
 
 import (
 	"fmt"
+	"os"
+	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 )
@@ -9,11 +12,34 @@ import (
 const fullPrefix = "--"
 const shortPrefix = "-"
 
+// ValueSource tells where the value of an argument comes from
+type ValueSource int
+
+const (
+	SourceUnset       ValueSource = iota // argument is not parsed
+	SourceDefault                        // value comes from Option.Default
+	SourceCommandLine                    // value is given by user input
+)
+
+func (s ValueSource) String() string {
+	switch s {
+	case SourceDefault:
+		return "default"
+	case SourceCommandLine:
+		return "command line"
+	}
+	return "unset"
+}
+
 type arg struct {
 	short    string
 	full     string
 	target   interface{}
-	assigned bool // whether the argument is parsed
+	assigned bool           // whether the argument is parsed
+	source   ValueSource    // where the parsed value comes from
+	pattern  *regexp.Regexp // compiled Option.Pattern
+	isPath   bool           // value is a path, created by File, Path or Paths
+	filePath string         // checked path of File argument, opened after parsing succeeds
 	Option
 }
 
@@ -32,10 +58,53 @@ type Option struct {
 	Group       string                                // argument group info, default to be no group
 	Inheritable bool                                  // sub parsers after this argument can inherit it
 	Action      func(args []string) error             // bind actions when the match is found, 'args' can be nil to be a flag
-	Choices     []interface{}                         // input argument must be one/some of the choice
+	Choices     []interface{}                         // input argument must be one/some of the choice, Choice for choice with help
 	Validate    func(arg string) error                // customize function to check argument validation
 	Formatter   func(arg string) (interface{}, error) // format input arguments by the given method
 	BindParsers []*Parser                             // specify parsers to bind
+	Deprecated  string                                // deprecation message, the argument still works but hidden, warns when it's used
+	ReplacedBy  string                                // forward value of deprecated argument to the argument, like 'time-to-live'
+
+	Aliases       []string // more full names of the argument, like 'expire' for --expire
+	HiddenAliases []string // more full names of the argument, hidden from help & completion
+
+	IgnoreCase bool // match string choices case-insensitively, the value in Choices is bound
+
+	Min     *float64 // minimum of int/float values, like Bound(1)
+	Max     *float64 // maximum of int/float values, like Bound(65535)
+	Pattern string   // regexp string values must match, like '^[a-z]+$'
+	MinLen  int      // minimum count of values for list argument
+	MaxLen  int      // maximum count of values for list argument, 0 for no limit
+
+	PathCheck PathCheck // checks for File, Path & Paths arguments, like PathMustExist | PathIsDir
+}
+
+// Bound returns pointer of the value, for Option.Min & Option.Max
+func Bound(v float64) *float64 {
+	return &v
+}
+
+// Choice is a choice with help message, usable in Option.Choices
+type Choice struct {
+	Value interface{} `json:"value"`
+	Help  string      `json:"help,omitempty"`
+}
+
+// choiceValue returns the value of the choice, which may be wrapped in Choice
+func choiceValue(c interface{}) interface{} {
+	if choice, ok := c.(Choice); ok {
+		return choice.Value
+	}
+	return c
+}
+
+// formatChoice returns the display value of the choice
+func formatChoice(c interface{}) string {
+	v := choiceValue(c)
+	if f, ok := v.(float64); ok {
+		return fmt.Sprintf("%f", f)
+	}
+	return fmt.Sprint(v)
 }
 
 // validate args setting before parsing args, right after adding to parser
@@ -56,6 +125,37 @@ func (a *arg) validate() error {
 	if a.short == a.full { // this will cause register conflict
 		return fmt.Errorf("arg short is full")
 	}
+	seen := make(map[string]bool)
+	for _, alias := range append(append([]string{}, a.Aliases...), a.HiddenAliases...) {
+		if seen[alias] { // this will cause register conflict
+			return fmt.Errorf("alias '%s' is repeated", alias)
+		}
+		seen[alias] = true
+		if a.Positional { // positional argument has no name to match
+			return fmt.Errorf("positional with alias")
+		}
+		if alias == "" || strings.Contains(alias, " ") {
+			return fmt.Errorf("alias '%s' is empty or with space", alias)
+		}
+		if strings.HasPrefix(alias, shortPrefix) { // alias will be auto prefixed
+			return fmt.Errorf("alias '%s' with extra prefix '%s'", alias, shortPrefix)
+		}
+		if alias == a.full { // this will cause register conflict
+			return fmt.Errorf("alias '%s' is full", alias)
+		}
+	}
+	if e := a.validateLimits(); e != nil {
+		return e
+	}
+	if e := a.validatePathCheck(); e != nil {
+		return e
+	}
+	if a.IgnoreCase && len(a.Choices) == 0 { // only choices are matched case-insensitively
+		return fmt.Errorf("ignore case without choices")
+	}
+	if a.ReplacedBy != "" && a.Deprecated == "" { // only deprecated argument is replaced
+		return fmt.Errorf("replaced without deprecation")
+	}
 	if a.isFlag {
 		if a.Positional { // positional argument can't be a flag, use flag instead
 			return fmt.Errorf("positional is a flag")
@@ -79,8 +179,52 @@ func (a *arg) validate() error {
 	return nil
 }
 
-// get argument watch list for parser use
+// validateLimits check Min, Max, Pattern, MinLen & MaxLen setting for the argument type
+func (a *arg) validateLimits() error {
+	if a.Min != nil || a.Max != nil {
+		switch a.target.(type) {
+		case *int, *[]int, *float64, *[]float64:
+		default:
+			return fmt.Errorf("min/max for non-number")
+		}
+		if a.Min != nil && a.Max != nil && *a.Min > *a.Max {
+			return fmt.Errorf("min is greater than max")
+		}
+	}
+	if a.Pattern != "" {
+		switch a.target.(type) {
+		case *string, *[]string:
+		default:
+			return fmt.Errorf("pattern for non-string")
+		}
+		pattern, e := regexp.Compile(a.Pattern)
+		if e != nil {
+			return fmt.Errorf("invalid pattern: %s", e.Error())
+		}
+		a.pattern = pattern
+	}
+	if a.MinLen != 0 || a.MaxLen != 0 {
+		if !a.multi {
+			return fmt.Errorf("min/max length for single value")
+		}
+		if a.MinLen < 0 || a.MaxLen < 0 || (a.MaxLen > 0 && a.MinLen > a.MaxLen) {
+			return fmt.Errorf("invalid min/max length")
+		}
+	}
+	return nil
+}
+
+// get argument watch list for parser use, hidden aliases included
 func (a *arg) getWatchers() []string {
+	result := a.getVisibleWatchers()
+	for _, alias := range a.HiddenAliases {
+		result = append(result, fmt.Sprintf("%s%s", fullPrefix, alias))
+	}
+	return result
+}
+
+// get argument watch list to show in help & completion
+func (a *arg) getVisibleWatchers() []string {
 	if a.Positional { // positional argument has nothing to watch, only positions
 		return []string{}
 	}
@@ -91,9 +235,22 @@ func (a *arg) getWatchers() []string {
 	if a.short != "" {
 		result = append(result, fmt.Sprintf("%s%s", shortPrefix, a.short))
 	}
+	for _, alias := range a.Aliases {
+		result = append(result, fmt.Sprintf("%s%s", fullPrefix, alias))
+	}
 	return result
 }
 
+// isHiddenAlias tells whether the entry is a hidden alias of the argument
+func (a *arg) isHiddenAlias(entry string) bool {
+	for _, alias := range a.HiddenAliases {
+		if entry == fullPrefix+alias {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *arg) getMetaName() string {
 	if a.Meta != "" {
 		return a.Meta // Meta variable given by programmer
@@ -101,8 +258,13 @@ func (a *arg) getMetaName() string {
 	return strings.ToUpper(a.getIdentifier())
 }
 
+// hidden tells whether the argument is hidden from usage, help & completion
+func (a *arg) hidden() bool {
+	return a.HideEntry || a.Deprecated != ""
+}
+
 func (a *arg) formatUsage() string {
-	if a.HideEntry {
+	if a.hidden() {
 		return ""
 	}
 
@@ -145,25 +307,54 @@ func (a *arg) getIdentifier() string {
 	return a.short
 }
 
+// getTypeName returns the value type name of the argument for documents
+func (a *arg) getTypeName() string {
+	switch a.target.(type) {
+	case *bool:
+		return "flag"
+	case **os.File:
+		return "file"
+	case *string:
+		if a.isPath {
+			return "path"
+		}
+		return "string"
+	case *[]string:
+		if a.isPath {
+			return "[]path"
+		}
+		return "[]string"
+	case *int:
+		return "int"
+	case *[]int:
+		return "[]int"
+	case *float64:
+		return "float64"
+	case *[]float64:
+		return "[]float64"
+	}
+	return ""
+}
+
 func (a *arg) formatHelpHeader(argument, meta Color) (size int, content string) {
 	metaName := a.getMetaName()
 	if a.Positional {
-		size = len(metaName)
+		size = displayWidth(metaName)
 		content = wrapperColor(metaName, argument)
 		return
 	}
 
 	wrapped := []string{}
-	watchers := a.getWatchers()
+	watchers := a.getVisibleWatchers()
 	for _, w := range watchers {
 		if a.isFlag {
 			wrapped = append(wrapped, wrapperColor(w, argument))
-			size += len(w)
+			size += displayWidth(w)
 		} else {
 			wrapped = append(wrapped,
 				fmt.Sprintf("%s %s", wrapperColor(w, argument),
 					wrapperColor(metaName, meta)))
-			size += len(w) + len(metaName) + 1
+			size += displayWidth(w) + displayWidth(metaName) + 1
 		}
 	}
 	size += (len(wrapped) - 1) * 2
@@ -186,6 +377,7 @@ func (a *arg) formatHelpWithExtraInfo() string {
 	if len(a.Choices) > 0 {
 		extraInfo = append(extraInfo, fmt.Sprintf("options: [%s]", a.dumpChoices()))
 	}
+	extraInfo = append(extraInfo, a.formatLimits()...)
 	if a.Required {
 		extraInfo = append(extraInfo, "required")
 	}
@@ -195,29 +387,158 @@ func (a *arg) formatHelpWithExtraInfo() string {
 	return help
 }
 
+// formatBound format the bound without useless zeros, like 1, 0.5
+func formatBound(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// formatLimits describe Min, Max, Pattern, MinLen & MaxLen of the argument, like 'range: 1 ~ 65535'
+func (a *arg) formatLimits() []string {
+	var result []string
+	switch {
+	case a.Min != nil && a.Max != nil:
+		result = append(result, fmt.Sprintf("range: %s ~ %s", formatBound(*a.Min), formatBound(*a.Max)))
+	case a.Min != nil:
+		result = append(result, fmt.Sprintf("min: %s", formatBound(*a.Min)))
+	case a.Max != nil:
+		result = append(result, fmt.Sprintf("max: %s", formatBound(*a.Max)))
+	}
+	if a.Pattern != "" {
+		result = append(result, fmt.Sprintf("pattern: %s", a.Pattern))
+	}
+	switch {
+	case a.MinLen > 0 && a.MaxLen > 0:
+		result = append(result, fmt.Sprintf("values: %d ~ %d", a.MinLen, a.MaxLen))
+	case a.MinLen > 0:
+		result = append(result, fmt.Sprintf("at least %d values", a.MinLen))
+	case a.MaxLen > 0:
+		result = append(result, fmt.Sprintf("at most %d values", a.MaxLen))
+	}
+	return result
+}
+
+// checkLimits check converted values by Min, Max & Pattern, expanded paths included
+func (a *arg) checkLimits(result []interface{}) error {
+	for _, r := range result {
+		var number *float64
+		switch v := r.(type) {
+		case int:
+			f := float64(v)
+			number = &f
+		case float64:
+			number = &v
+		case string:
+			if a.pattern != nil && !a.pattern.MatchString(v) {
+				return InvalidValueError{Argument: a.getIdentifier(), Token: v,
+					Err: fmt.Errorf("value %s doesn't match pattern %s", v, a.Pattern)}
+			}
+		}
+		if number == nil {
+			continue
+		}
+		token := formatBound(*number)
+		if a.Min != nil && *number < *a.Min {
+			return InvalidValueError{Argument: a.getIdentifier(), Token: token,
+				Err: fmt.Errorf("value %s is less than %s", token, formatBound(*a.Min))}
+		}
+		if a.Max != nil && *number > *a.Max {
+			return InvalidValueError{Argument: a.getIdentifier(), Token: token,
+				Err: fmt.Errorf("value %s is greater than %s", token, formatBound(*a.Max))}
+		}
+	}
+	return nil
+}
+
+// checkCount check count of values bound to list argument by MinLen & MaxLen
+func (a *arg) checkCount() error {
+	count := 0
+	switch target := a.target.(type) {
+	case *[]string:
+		count = len(*target)
+	case *[]int:
+		count = len(*target)
+	case *[]float64:
+		count = len(*target)
+	default:
+		return nil
+	}
+	if count < a.MinLen {
+		return InvalidValueError{Argument: a.getIdentifier(),
+			Err: fmt.Errorf("argument %s expect at least %d values, got %d", a.getIdentifier(), a.MinLen, count)}
+	}
+	if a.MaxLen > 0 && count > a.MaxLen {
+		return InvalidValueError{Argument: a.getIdentifier(),
+			Err: fmt.Errorf("argument %s expect at most %d values, got %d", a.getIdentifier(), a.MaxLen, count)}
+	}
+	return nil
+}
+
 func (a *arg) dumpChoices() string {
 	var choices []string
-	e := a.Choices[0]
-	switch e.(type) {
-	case string:
-		for _, e := range a.Choices {
-			choices = append(choices, e.(string))
+	for _, c := range a.Choices {
+		choices = append(choices, formatChoice(c))
+	}
+	return strings.Join(choices, ", ")
+}
+
+// hasChoiceHelp tells whether any choice has help message
+func (a *arg) hasChoiceHelp() bool {
+	for _, c := range a.Choices {
+		if choice, ok := c.(Choice); ok && choice.Help != "" {
+			return true
 		}
-	case int:
-		for _, e := range a.Choices {
-			choices = append(choices, fmt.Sprintf("%d", e.(int)))
+	}
+	return false
+}
+
+// formatChoiceTable format choices with their help messages as rows indented by headerLength
+func (a *arg) formatChoiceTable(meta Color, headerLength, terminalWidth int) string {
+	valueLength := 0
+	for _, c := range a.Choices {
+		if l := displayWidth(formatChoice(c)); l > valueLength {
+			valueLength = l
 		}
-	case float64:
-		for _, e := range a.Choices {
-			choices = append(choices, fmt.Sprintf("%f", e.(float64)))
+	}
+	padding := strings.Repeat(" ", headerLength)
+	var rows []string
+	for _, c := range a.Choices {
+		value := formatChoice(c)
+		help := ""
+		if choice, ok := c.(Choice); ok {
+			help = choice.Help
+		}
+		row := formatHelpRow(wrapperColor(value, meta), help, displayWidth(value), valueLength+4,
+			terminalWidth-headerLength, false)
+		for _, line := range strings.Split(row, "\n") {
+			rows = append(rows, strings.TrimRight(padding+line, " "))
 		}
 	}
-	return strings.Join(choices, ", ")
+	return strings.Join(rows, "\n")
+}
+
+// matchChoice find the choice matching the parsed value, values are compared deeply,
+// strings are compared case-insensitively if IgnoreCase is set
+func (a *arg) matchChoice(r interface{}) (interface{}, bool) {
+	for _, c := range a.Choices {
+		v := choiceValue(c)
+		if reflect.DeepEqual(v, r) {
+			return v, true
+		}
+		if a.IgnoreCase {
+			expect, ok := v.(string)
+			input, isString := r.(string)
+			if ok && isString && strings.EqualFold(expect, input) {
+				return v, true
+			}
+		}
+	}
+	return nil, false
 }
 
 // parse input & bind (default) value to target
 func (a *arg) parseValue(values []string) error {
 	a.assigned = true
+	a.source = SourceCommandLine // Parser marks it SourceDefault when binding default value
 	if a.Action != nil {
 		return a.Action(values)
 	}
@@ -232,7 +553,7 @@ func (a *arg) parseValue(values []string) error {
 		for _, v := range values {
 			e := a.Validate(v)
 			if e != nil {
-				return e
+				return InvalidValueError{Argument: a.getIdentifier(), Token: v, Err: e}
 			}
 		}
 	}
@@ -241,13 +562,21 @@ func (a *arg) parseValue(values []string) error {
 		for _, v := range values {
 			f, e := a.Formatter(v)
 			if e != nil {
-				return e
+				return InvalidValueError{Argument: a.getIdentifier(), Token: v, Err: e}
 			}
 			result = append(result, f)
 		}
 	} else {
 		switch a.target.(type) {
-		case *string, *[]string:
+		case *string, *[]string, **os.File:
+			if a.isPath {
+				paths, e := a.resolvePaths(values)
+				if e != nil {
+					return e
+				}
+				result = paths
+				break
+			}
 			for _, v := range values {
 				result = append(result, v)
 			}
@@ -255,7 +584,7 @@ func (a *arg) parseValue(values []string) error {
 			for _, raw := range values {
 				v, e := strconv.Atoi(raw)
 				if e != nil {
-					return fmt.Errorf("invalid int value: %s", raw)
+					return InvalidValueError{Argument: a.getIdentifier(), Token: raw, Type: "int"}
 				}
 				result = append(result, v)
 			}
@@ -263,29 +592,30 @@ func (a *arg) parseValue(values []string) error {
 			for _, raw := range values {
 				v, e := strconv.ParseFloat(raw, 64)
 				if e != nil {
-					return fmt.Errorf("invalid float value: %s", raw)
+					return InvalidValueError{Argument: a.getIdentifier(), Token: raw, Type: "float"}
 				}
 				result = append(result, v)
 			}
 		}
 	}
+	if e := a.checkLimits(result); e != nil {
+		return e
+	}
 	//if len(result) == 0 {
 	//	return fmt.Errorf("no value to parse") // normally you can't reach this area
 	//}
 	if len(a.Choices) > 0 { // check if user input is among given Choices
-		for _, r := range result {
-			found := false
-			for _, c := range a.Choices {
-				if c == r {
-					found = true
-				}
-			}
+		for i, r := range result {
+			v, found := a.matchChoice(r)
 			if !found {
-				return fmt.Errorf("args must be one|some of %+v", a.Choices)
+				return ChoiceError{Argument: a.getIdentifier(), Token: fmt.Sprint(r), Choices: a.Choices}
 			}
+			result[i] = v
 		}
 	}
 	switch a.target.(type) { // bind different types
+	case **os.File:
+		a.filePath = result[0].(string)
 	case *string:
 		*a.target.(*string) = result[0].(string)
 	case *int:
@@ -74,6 +74,16 @@ func TestArgs(t *testing.T) {
 			return
 		}
 	}
+	if e := (&arg{full: "a", Option: Option{ReplacedBy: "b"}}).validate(); e != nil {
+		if e.Error() != "replaced without deprecation" {
+			t.Error("replaced without deprecation")
+			return
+		}
+	}
+	if e := (&arg{full: "a", Option: Option{IgnoreCase: true}}).validate(); e == nil || e.Error() != "ignore case without choices" {
+		t.Error("ignore case without choices")
+		return
+	}
 }
 
 func TestArgs_HideEntry(t *testing.T) {
@@ -101,6 +111,10 @@ func TestExtraInfo(t *testing.T) {
 		t.Error("failed to generate choices for float")
 		return
 	}
+	if (&arg{full: "a", Option: Option{Choices: []interface{}{Choice{Value: "x", Help: "x"}, true}}}).formatHelpWithExtraInfo() != "(options: [x, true])" {
+		t.Error("failed to generate choices for any type")
+		return
+	}
 }
 
 func TestFormatUsagePositional(t *testing.T) {
@@ -113,3 +127,25 @@ func TestFormatUsagePositional(t *testing.T) {
 		return
 	}
 }
+
+func TestValidateLimits(t *testing.T) {
+	var s string
+	var i int
+	var is []int
+	for _, c := range []struct {
+		a       *arg
+		message string
+	}{
+		{&arg{full: "a", target: &s, Option: Option{Min: Bound(1)}}, "min/max for non-number"},
+		{&arg{full: "a", target: &i, Option: Option{Min: Bound(2), Max: Bound(1)}}, "min is greater than max"},
+		{&arg{full: "a", target: &i, Option: Option{Pattern: "a"}}, "pattern for non-string"},
+		{&arg{full: "a", target: &s, Option: Option{Pattern: "("}}, "invalid pattern: error parsing regexp: missing closing ): `(`"},
+		{&arg{full: "a", target: &i, Option: Option{MinLen: 1}}, "min/max length for single value"},
+		{&arg{full: "a", target: &is, Option: Option{MinLen: 3, MaxLen: 2, multi: true}}, "invalid min/max length"},
+	} {
+		if e := c.a.validate(); e == nil || e.Error() != c.message {
+			t.Error("failed to validate limits: " + c.message)
+			return
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package argparse
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefinitionError collects all problems found by Builder
+type DefinitionError struct {
+	Errors []error
+}
+
+func (e DefinitionError) Error() string {
+	var messages []string
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Builder register arguments & sub commands to a parser without panic
+//
+// it has the same methods as Parser to create arguments, but definition problems are collected
+// and reported all at once by Build, sub commands included. it's useful when arguments are defined by user given data
+type Builder struct {
+	parser *Parser
+	errors *[]error // shared by builders of sub commands
+}
+
+// Builder create a Builder to register arguments to the parser without panic
+func (p *Parser) Builder() *Builder {
+	return &Builder{parser: p, errors: &[]error{}}
+}
+
+// Parser returns the parser the builder registers to
+func (b *Builder) Parser() *Parser {
+	return b.parser
+}
+
+func (b *Builder) fail(e error) {
+	if b.parser.parent != nil { // problems of sub commands are prefixed with the command path
+		e = fmt.Errorf("command '%s': %w", strings.Join(b.parser.commandPath()[1:], " "), e)
+	}
+	*b.errors = append(*b.errors, e)
+}
+
+func (b *Builder) register(a *arg) {
+	if e := b.parser.registerArgument(a); e != nil {
+		if id := a.getIdentifier(); id != "" {
+			e = fmt.Errorf("argument '%s': %w", id, e)
+		}
+		b.fail(e)
+	}
+}
+
+// Build returns a DefinitionError if there is any problem during registration, or nil
+func (b *Builder) Build() error {
+	if len(*b.errors) > 0 {
+		return DefinitionError{Errors: *b.errors}
+	}
+	return nil
+}
+
+// AddCommand add sub command entry parser like Parser.AddCommand
+//
+// the builder of the sub command is always returned to continue the definition, sharing problems with current builder,
+// but the sub command is not registered if there's any problem
+func (b *Builder) AddCommand(name string, description string, config *ParserConfig) *Builder {
+	parser, e := b.parser.addCommand(name, description, config)
+	if e != nil {
+		path := strings.Join(append(b.parser.commandPath()[1:], name), " ")
+		*b.errors = append(*b.errors, fmt.Errorf("command '%s': %w", path, e))
+	}
+	return &Builder{parser: parser, errors: b.errors}
+}
+
+// Flag create flag argument like Parser.Flag
+func (b *Builder) Flag(short, full string, opts *Option) *bool {
+	var result bool
+	b.register(newArg(short, full, &result, opts))
+	return &result
+}
+
+// String create string argument like Parser.String
+func (b *Builder) String(short, full string, opts *Option) *string {
+	var result string
+	b.register(newArg(short, full, &result, opts))
+	return &result
+}
+
+// Strings create string list argument like Parser.Strings
+func (b *Builder) Strings(short, full string, opts *Option) *[]string {
+	var result []string
+	b.register(newArg(short, full, &result, opts))
+	return &result
+}
+
+// Int create int argument like Parser.Int
+func (b *Builder) Int(short, full string, opts *Option) *int {
+	var result int
+	b.register(newArg(short, full, &result, opts))
+	return &result
+}
+
+// Ints create int list argument like Parser.Ints
+func (b *Builder) Ints(short, full string, opts *Option) *[]int {
+	var result []int
+	b.register(newArg(short, full, &result, opts))
+	return &result
+}
+
+// Float create float argument like Parser.Float
+func (b *Builder) Float(short, full string, opts *Option) *float64 {
+	var result float64
+	b.register(newArg(short, full, &result, opts))
+	return &result
+}
+
+// Floats create float list argument like Parser.Floats
+func (b *Builder) Floats(short, full string, opts *Option) *[]float64 {
+	var result []float64
+	b.register(newArg(short, full, &result, opts))
+	return &result
+}
+
+// File create file argument like Parser.File
+func (b *Builder) File(short, full string, opts *Option) **os.File {
+	var result *os.File
+	b.register(newPathArg(short, full, &result, opts))
+	return &result
+}
+
+// Path create path argument like Parser.Path
+func (b *Builder) Path(short, full string, opts *Option) *string {
+	var result string
+	b.register(newPathArg(short, full, &result, opts))
+	return &result
+}
+
+// Paths create path list argument like Parser.Paths
+func (b *Builder) Paths(short, full string, opts *Option) *[]string {
+	var result []string
+	b.register(newPathArg(short, full, &result, opts))
+	return &result
+}
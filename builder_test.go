@@ -0,0 +1,69 @@
+package argparse
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	p := NewParser("", "", nil)
+	b := p.Builder()
+	name := b.String("n", "name", nil)
+	b.Int("", "name", nil)
+	b.Flag("v", "v", nil)
+	b.Strings("", "bad name", nil)
+	b.AddCommand("a b", "", nil)
+	sub := b.AddCommand("sub", "", nil)
+	b.AddCommand("sub", "", nil)
+	x := sub.Floats("x", "", &Option{Positional: true})
+	sub.Int("", "bad name", nil)
+	sub.AddCommand("nested", "", nil).Flag("", "", nil)
+	e := b.Build()
+	var definition DefinitionError
+	if !errors.As(e, &definition) || len(definition.Errors) != 7 {
+		t.Error("failed to collect all problems")
+		return
+	}
+	var conflict ConflictError
+	if !errors.As(definition.Errors[0], &conflict) || conflict.Name != "--name" {
+		t.Error("failed to keep conflict error")
+		return
+	}
+	if !strings.Contains(e.Error(), "argument 'v': arg short is full") ||
+		!strings.Contains(e.Error(), "command 'a b': sub command name has space") ||
+		!strings.Contains(e.Error(), "command 'sub': argument 'bad name': ") ||
+		!strings.Contains(e.Error(), "command 'sub nested': ") {
+		t.Error("failed to report problems")
+		return
+	}
+	if len(p.Arguments()) != 2 || len(p.Commands()) != 1 || sub.Parser().Name() != "sub" {
+		t.Error("invalid definition should not be registered")
+		return
+	}
+	if e := p.Parse([]string{"-n", "flame"}); e != nil || *name != "flame" {
+		t.Error("failed to parse built arguments")
+		return
+	}
+	if e := p.Parse([]string{"sub", "0.5", "1"}); e != nil || len(*x) != 2 {
+		t.Error("failed to parse sub command arguments")
+		return
+	}
+
+	if e := NewParser("", "", nil).Builder().Build(); e != nil {
+		t.Error("there should be no problem")
+		return
+	}
+}
+
+func TestBuilderPath(t *testing.T) {
+	b := NewParser("", "", nil).Builder()
+	b.File("", "input", &Option{PathCheck: PathIsDir})
+	b.Path("", "dir", &Option{PathCheck: PathIsDir | PathIsRegular})
+	workdir := b.Paths("", "workdir", nil)
+	if e := b.Build(); e == nil || !strings.Contains(e.Error(), "argument 'input': file is dir") ||
+		!strings.Contains(e.Error(), "argument 'dir': path is dir & regular file") || workdir == nil {
+		t.Error("failed to check path definitions")
+		return
+	}
+}
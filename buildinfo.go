@@ -0,0 +1,42 @@
+//go:build go1.18
+// +build go1.18
+
+package argparse
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+// BuildVersion returns version of the program from its build info, it's usable as ParserConfig.VersionFunc
+//
+// the result is like 'v1.2.0 (rev 3a4b5c6, dirty, committed at 2021-01-02T15:04:05Z)',
+// module version is '(devel)' when the program is not installed by a version
+func BuildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	var extra []string
+	settings := make(map[string]string)
+	for _, s := range info.Settings {
+		settings[s.Key] = s.Value
+	}
+	if revision := settings["vcs.revision"]; revision != "" {
+		if len(revision) > 7 {
+			revision = revision[:7]
+		}
+		extra = append(extra, "rev "+revision)
+	}
+	if settings["vcs.modified"] == "true" {
+		extra = append(extra, "dirty")
+	}
+	if t := settings["vcs.time"]; t != "" {
+		extra = append(extra, "committed at "+t)
+	}
+	if len(extra) == 0 {
+		return info.Main.Version
+	}
+	return fmt.Sprintf("%s (%s)", info.Main.Version, strings.Join(extra, ", "))
+}
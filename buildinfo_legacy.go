@@ -0,0 +1,19 @@
+//go:build !go1.18
+// +build !go1.18
+
+package argparse
+
+import (
+	"runtime/debug"
+)
+
+// BuildVersion returns version of the program from its build info, it's usable as ParserConfig.VersionFunc
+//
+// vcs info is only available since go1.18, only module version is returned here
+func BuildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	return info.Main.Version
+}
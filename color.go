@@ -2,13 +2,64 @@ package argparse
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
 )
 
 type Color struct {
 	Code     int // color code for text color or background color, normally within 30 ~ 49
 	Property int // property code, eg: 1 for bold, 4 for underline, etc.
+
+	Properties []int     // more property codes, like []int{1, 4} for bold & underline
+	Foreground *ExtColor // 256-color or true color for text, like Color256(208), ColorRGB(255, 135, 0)
+	Background *ExtColor // 256-color or true color for background
+}
+
+// ExtColor is a color beyond basic color codes, an index in 256-color palette or a true color
+type ExtColor struct {
+	Index   int   // index in 256-color palette, 0 ~ 255
+	R, G, B uint8 // true color, used when IsRGB is set
+	IsRGB   bool
+}
+
+// Color256 returns the color in 256-color palette
+func Color256(index int) *ExtColor {
+	return &ExtColor{Index: index}
+}
+
+// ColorRGB returns the true color
+func ColorRGB(r, g, b uint8) *ExtColor {
+	return &ExtColor{R: r, G: g, B: b, IsRGB: true}
+}
+
+// sgr returns the select graphic rendition parameters of the color, base is 38 for foreground, 48 for background
+func (c *ExtColor) sgr(base int) string {
+	if c.IsRGB {
+		return fmt.Sprintf("%d;2;%d;%d;%d", base, c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("%d;5;%d", base, c.Index)
+}
+
+// sgr returns the select graphic rendition parameters of the color, like '1;36', empty for no color
+func (c Color) sgr() string {
+	var params []string
+	for _, p := range append([]int{c.Property}, c.Properties...) {
+		if p != 0 {
+			params = append(params, strconv.Itoa(p))
+		}
+	}
+	if c.Code != 0 {
+		params = append(params, strconv.Itoa(c.Code))
+	}
+	if c.Foreground != nil {
+		params = append(params, c.Foreground.sgr(38))
+	}
+	if c.Background != nil {
+		params = append(params, c.Background.sgr(48))
+	}
+	return strings.Join(params, ";")
 }
 
 type ColorSchema struct {
@@ -22,6 +73,10 @@ type ColorSchema struct {
 	Meta     Color
 
 	Epilog Color
+
+	Error      Color // error label, like 'tool: error:'
+	Warning    Color // warning label, like 'warning:'
+	Suggestion Color // suggested entries for unknown arguments
 }
 
 // Just black & white
@@ -29,20 +84,90 @@ var NoColor = &ColorSchema{}
 
 // Default color schema
 var DefaultColor = &ColorSchema{
-	Usage:      Color{37, 1},
-	GroupTitle: Color{32, 1},
-	Command:    Color{33, 1},
-	Argument:   Color{36, 0},
-	Epilog:     Color{37, 1},
+	Usage:      Color{Code: 37, Property: 1},
+	GroupTitle: Color{Code: 32, Property: 1},
+	Command:    Color{Code: 33, Property: 1},
+	Argument:   Color{Code: 36},
+	Epilog:     Color{Code: 37, Property: 1},
+	Error:      Color{Code: 31, Property: 1},
+	Warning:    Color{Code: 33, Property: 1},
+	Suggestion: Color{Code: 32},
+}
+
+// Solarized color schema in 256-color palette
+var SolarizedColor = &ColorSchema{
+	Usage:      Color{Property: 1, Foreground: Color256(33)},
+	GroupTitle: Color{Property: 1, Foreground: Color256(136)},
+	Command:    Color{Foreground: Color256(37)},
+	Argument:   Color{Foreground: Color256(64)},
+	Meta:       Color{Foreground: Color256(245)},
+	Epilog:     Color{Foreground: Color256(245)},
+	Error:      Color{Property: 1, Foreground: Color256(160)},
+	Warning:    Color{Property: 1, Foreground: Color256(136)},
+	Suggestion: Color{Foreground: Color256(64)},
+}
+
+// Monokai color schema in 256-color palette
+var MonokaiColor = &ColorSchema{
+	Usage:      Color{Property: 1, Foreground: Color256(81)},
+	GroupTitle: Color{Property: 1, Foreground: Color256(197)},
+	Command:    Color{Foreground: Color256(148)},
+	Argument:   Color{Foreground: Color256(208)},
+	Meta:       Color{Foreground: Color256(141)},
+	Epilog:     Color{Foreground: Color256(242)},
+	Error:      Color{Property: 1, Foreground: Color256(197)},
+	Warning:    Color{Property: 1, Foreground: Color256(186)},
+	Suggestion: Color{Foreground: Color256(148)},
+}
+
+// Nord color schema in true color
+var NordColor = &ColorSchema{
+	Usage:      Color{Property: 1, Foreground: ColorRGB(136, 192, 208)},
+	GroupTitle: Color{Property: 1, Foreground: ColorRGB(129, 161, 193)},
+	Command:    Color{Foreground: ColorRGB(163, 190, 140)},
+	Argument:   Color{Foreground: ColorRGB(143, 188, 187)},
+	Meta:       Color{Foreground: ColorRGB(180, 142, 173)},
+	Epilog:     Color{Foreground: ColorRGB(216, 222, 233)},
+	Error:      Color{Property: 1, Foreground: ColorRGB(191, 97, 106)},
+	Warning:    Color{Property: 1, Foreground: ColorRGB(235, 203, 139)},
+	Suggestion: Color{Foreground: ColorRGB(163, 190, 140)},
+}
+
+// ColorThemes are named color schemas, like 'solarized'
+var ColorThemes = map[string]*ColorSchema{
+	"none":      NoColor,
+	"default":   DefaultColor,
+	"solarized": SolarizedColor,
+	"monokai":   MonokaiColor,
+	"nord":      NordColor,
 }
 
-func checkTerminalColorSupport() bool {
-	return strings.Contains(os.Getenv("TERM"), "color")
+// checkTerminalColorSupport decide whether color is used for the output, by the conventions in order:
+// NO_COLOR disables color, FORCE_COLOR/CLICOLOR_FORCE enables color,
+// output which is not a terminal has no color, then TERM & COLORTERM tell the terminal support
+func checkTerminalColorSupport(out io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	for _, env := range []string{"FORCE_COLOR", "CLICOLOR_FORCE"} {
+		if v := os.Getenv(env); v != "" {
+			return v != "0" && v != "false"
+		}
+	}
+	if !isTerminal(out) {
+		return false
+	}
+	term := os.Getenv("TERM")
+	if term == "dumb" {
+		return false
+	}
+	return strings.Contains(term, "color") || os.Getenv("COLORTERM") != ""
 }
 
 func wrapperColor(content string, color Color) string {
-	if color.Code == 0 && color.Property == 0 {
+	sgr := color.sgr()
+	if sgr == "" {
 		return content
 	}
-	return fmt.Sprintf("\033[0%d;%dm%s\033[00m", color.Property, color.Code, content)
+	return fmt.Sprintf("\033[%sm%s\033[00m", sgr, content)
 }
@@ -0,0 +1,86 @@
+package argparse
+
+import (
+	"os"
+	"testing"
+)
+
+func TestWrapperColor(t *testing.T) {
+	if wrapperColor("a", Color{}) != "a" {
+		t.Error("empty color should not wrap")
+		return
+	}
+	if wrapperColor("a", Color{Code: 36}) != "\033[36ma\033[00m" {
+		t.Error("failed to wrap basic color")
+		return
+	}
+	if wrapperColor("a", Color{Code: 32, Property: 1, Properties: []int{4}}) != "\033[1;4;32ma\033[00m" {
+		t.Error("failed to wrap multiple properties")
+		return
+	}
+	if wrapperColor("a", Color{Foreground: Color256(208), Background: ColorRGB(1, 2, 3)}) !=
+		"\033[38;5;208;48;2;1;2;3ma\033[00m" {
+		t.Error("failed to wrap extended colors")
+		return
+	}
+	for name, theme := range ColorThemes {
+		if name != "none" && wrapperColor("a", theme.GroupTitle) == "a" {
+			t.Error("theme without color: " + name)
+			return
+		}
+	}
+}
+
+func TestColorSupport(t *testing.T) {
+	envs := []string{"NO_COLOR", "FORCE_COLOR", "CLICOLOR_FORCE", "TERM", "COLORTERM"}
+	backup := make(map[string]string)
+	for _, env := range envs {
+		if v, ok := os.LookupEnv(env); ok {
+			backup[env] = v
+		}
+		os.Unsetenv(env)
+	}
+	defer func() {
+		for _, env := range envs {
+			if v, ok := backup[env]; ok {
+				os.Setenv(env, v)
+			} else {
+				os.Unsetenv(env)
+			}
+		}
+	}()
+	out, e := os.CreateTemp("", "")
+	if e != nil {
+		t.Error(e.Error())
+		return
+	}
+	defer os.Remove(out.Name())
+	defer out.Close()
+	os.Setenv("TERM", "xterm-256color")
+	if checkTerminalColorSupport(out) {
+		t.Error("output which is not a terminal should have no color")
+		return
+	}
+	os.Setenv("FORCE_COLOR", "1")
+	if !checkTerminalColorSupport(out) {
+		t.Error("FORCE_COLOR should enable color")
+		return
+	}
+	os.Setenv("FORCE_COLOR", "0")
+	if checkTerminalColorSupport(out) {
+		t.Error("FORCE_COLOR=0 should disable color")
+		return
+	}
+	os.Unsetenv("FORCE_COLOR")
+	os.Setenv("CLICOLOR_FORCE", "1")
+	os.Setenv("NO_COLOR", "1")
+	if checkTerminalColorSupport(out) {
+		t.Error("NO_COLOR should disable color")
+		return
+	}
+	p := NewParser("", "", &ParserConfig{WithColor: true, EnsureColor: true})
+	if wrapperColor("a", DefaultColor.Usage) == "a" || p.FormatHelp() == p.FormatHelpWithColor(NoColor) {
+		t.Error("EnsureColor should skip env check")
+		return
+	}
+}
@@ -0,0 +1,116 @@
+package argparse
+
+import (
+	"encoding/json"
+)
+
+// ArgumentDescription is the machine-readable description of an argument
+type ArgumentDescription struct {
+	Name        string        `json:"name"`                  // identifier of the argument, full name first
+	Short       string        `json:"short,omitempty"`       // short name without prefix
+	Full        string        `json:"full,omitempty"`        // full name without prefix
+	Aliases     []string      `json:"aliases,omitempty"`     // more full names shown in help
+	Meta        string        `json:"meta,omitempty"`        // meta name shown in usage, empty for flag
+	Positional  bool          `json:"positional"`            // is positional argument
+	Position    int           `json:"position,omitempty"`    // order of positional argument, starting from 1
+	Type        string        `json:"type"`                  // value type, like flag, string, []int
+	Multiple    bool          `json:"multiple"`              // take more than one argument
+	Default     string        `json:"default,omitempty"`     // default argument value
+	Choices     []interface{} `json:"choices,omitempty"`     // input argument must be one/some of the choice
+	Required    bool          `json:"required"`              // require to be set
+	Group       string        `json:"group,omitempty"`       // argument group
+	Hidden      bool          `json:"hidden"`                // hidden from usage & help
+	Inheritable bool          `json:"inheritable"`           // sub parsers after this argument can inherit it
+	Help        string        `json:"help,omitempty"`        // help message
+	Deprecated  string        `json:"deprecated,omitempty"`  // deprecation message
+	ReplacedBy  string        `json:"replaced_by,omitempty"` // replacement of the deprecated argument
+	Min         *float64      `json:"min,omitempty"`         // minimum of number values
+	Max         *float64      `json:"max,omitempty"`         // maximum of number values
+	Pattern     string        `json:"pattern,omitempty"`     // regexp string values must match
+	MinLen      int           `json:"min_len,omitempty"`     // minimum count of values
+	MaxLen      int           `json:"max_len,omitempty"`     // maximum count of values
+}
+
+// ParserDescription is the machine-readable description of a parser & its sub commands
+type ParserDescription struct {
+	Name        string                `json:"name"`
+	Path        []string              `json:"path"` // command path from the root parser
+	Description string                `json:"description,omitempty"`
+	Usage       string                `json:"usage"`
+	EpiLog      string                `json:"epilog,omitempty"`
+	Category    string                `json:"category,omitempty"`   // category in parent's help
+	Hidden      bool                  `json:"hidden"`               // hidden from parent's help & completion
+	Deprecated  string                `json:"deprecated,omitempty"` // deprecation message
+	Groups      []string              `json:"groups,omitempty"`     // argument groups in order
+	Arguments   []ArgumentDescription `json:"arguments"`            // optional arguments first, then positionals in order
+	Examples    []Example             `json:"examples,omitempty"`
+	Commands    []ParserDescription   `json:"commands,omitempty"`
+}
+
+func (a *arg) describe() ArgumentDescription {
+	d := ArgumentDescription{
+		Name:        a.getIdentifier(),
+		Short:       a.short,
+		Full:        a.full,
+		Aliases:     a.Aliases,
+		Positional:  a.Positional,
+		Type:        a.getTypeName(),
+		Multiple:    a.multi,
+		Default:     a.Default,
+		Choices:     a.Choices,
+		Required:    a.Required,
+		Group:       a.Group,
+		Hidden:      a.hidden(),
+		Inheritable: a.Inheritable,
+		Help:        a.Help,
+		Deprecated:  a.Deprecated,
+		ReplacedBy:  a.ReplacedBy,
+		Min:         a.Min,
+		Max:         a.Max,
+		Pattern:     a.Pattern,
+		MinLen:      a.MinLen,
+		MaxLen:      a.MaxLen,
+	}
+	if !a.isFlag {
+		d.Meta = a.getMetaName()
+	}
+	return d
+}
+
+// Describe export definitions of the parser & all its sub commands
+func (p *Parser) Describe() ParserDescription {
+	d := ParserDescription{
+		Name:        p.name,
+		Path:        p.commandPath(),
+		Description: p.description,
+		Usage:       p.formatUsage(),
+		EpiLog:      p.config.EpiLog,
+		Hidden:      p.hidden(),
+		Deprecated:  p.deprecated(),
+		Groups:      p.entryGroupOrder,
+		Arguments:   []ArgumentDescription{},
+		Examples:    p.examples(),
+	}
+	position := 0
+	for _, a := range p.allArguments() {
+		arg := a.describe()
+		if a.Positional {
+			position += 1
+			arg.Position = position
+		}
+		d.Arguments = append(d.Arguments, arg)
+	}
+	for _, parser := range p.subParser {
+		sub := parser.Describe()
+		if parser.config != p.config {
+			sub.Category = parser.config.Category
+		}
+		d.Commands = append(d.Commands, sub)
+	}
+	return d
+}
+
+// DescribeJSON export definitions of the parser & all its sub commands as json
+func (p *Parser) DescribeJSON() ([]byte, error) {
+	return json.MarshalIndent(p.Describe(), "", "  ")
+}
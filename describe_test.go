@@ -0,0 +1,85 @@
+package argparse
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	p := NewParser("tool", "this is a tool", nil)
+	p.Ints("p", "port", &Option{Default: "8080", Choices: []interface{}{8080, 9090}, Group: "net"})
+	p.String("", "secret", &Option{HideEntry: true, Inheritable: true})
+	p.String("", "src", &Option{Positional: true, Required: true})
+	p.String("", "dst", &Option{Positional: true, Meta: "DEST"})
+	deploy := p.AddCommand("deploy", "deploy the app", nil)
+	deploy.Flag("f", "force", nil)
+
+	d := p.Describe()
+	if d.Name != "tool" || len(d.Commands) != 1 || len(d.Groups) != 1 || len(d.Arguments) != 5 {
+		t.Error("failed to describe parser")
+		return
+	}
+	port := d.Arguments[1]
+	if port.Name != "port" || port.Short != "p" || port.Type != "[]int" || !port.Multiple ||
+		port.Default != "8080" || len(port.Choices) != 2 || port.Group != "net" {
+		t.Error("failed to describe argument")
+		return
+	}
+	if !d.Arguments[2].Hidden || !d.Arguments[2].Inheritable {
+		t.Error("failed to describe hidden argument")
+		return
+	}
+	if d.Arguments[3].Position != 1 || !d.Arguments[3].Required || d.Arguments[4].Position != 2 || d.Arguments[4].Meta != "DEST" {
+		t.Error("failed to describe positionals")
+		return
+	}
+	sub := d.Commands[0]
+	if len(sub.Path) != 2 || sub.Path[0] != "tool" || sub.Arguments[1].Name != "secret" || sub.Arguments[2].Type != "flag" || sub.Arguments[2].Meta != "" {
+		t.Error("failed to describe sub command")
+		return
+	}
+
+	content, e := p.DescribeJSON()
+	if e != nil {
+		t.Error(e)
+		return
+	}
+	var decoded ParserDescription
+	if e := json.Unmarshal(content, &decoded); e != nil || decoded.Commands[0].Name != "deploy" {
+		t.Error("failed to decode json description")
+		return
+	}
+
+	p = NewParser("tool", "", nil)
+	p.String("", "ttl", &Option{Deprecated: "no more ttl"})
+	p.AddCommand("old", "", &ParserConfig{Deprecated: "use new instead"})
+	d = p.Describe()
+	if !d.Arguments[1].Hidden || !d.Commands[0].Hidden {
+		t.Error("deprecated entries should be described hidden")
+		return
+	}
+}
+
+func TestHelpJSON(t *testing.T) {
+	var out bytes.Buffer
+	p := NewParser("tool", "", &ParserConfig{AddHelpJSON: true, Stdout: &out})
+	p.String("n", "name", nil)
+	if e := p.Parse([]string{"--help-json"}); e != BreakAfterHelpError {
+		t.Error("failed to break after json description")
+		return
+	}
+	var d ParserDescription
+	if e := json.Unmarshal(out.Bytes(), &d); e != nil {
+		t.Error(e.Error())
+		return
+	}
+	if d.Name != "tool" || len(d.Arguments) == 0 || d.Arguments[len(d.Arguments)-1].Name != "name" {
+		t.Error("failed to print json description")
+		return
+	}
+	if len(p.visibleOptions()) != 2 {
+		t.Error("json description entry should be hidden")
+		return
+	}
+}
@@ -1,5 +1,10 @@
 package argparse
 
+import (
+	"fmt"
+	"strings"
+)
+
 // BreakAfterHelp will be thrown after help showed
 type BreakAfterHelp struct {
 }
@@ -16,8 +21,240 @@ func (b BreakAfterShellScript) Error() string {
 	return ""
 }
 
+// BreakAfterVersion will be thrown after version showed
+type BreakAfterVersion struct {
+}
+
+func (b BreakAfterVersion) Error() string {
+	return ""
+}
+
 // BreakAfterHelpError indicates that is's a break after help call
 var BreakAfterHelpError = BreakAfterHelp{}
 
 // BreakAfterShellScriptError indicates that it's a break after shell script call
 var BreakAfterShellScriptError = BreakAfterShellScript{}
+
+// BreakAfterVersionError indicates that it's a break after version call
+var BreakAfterVersionError = BreakAfterVersion{}
+
+// ErrorCode tells the category of a typed error
+type ErrorCode int
+
+const (
+	CodeUnknownArgument ErrorCode = iota + 1 // UnknownArgumentError
+	CodeMissingValue                         // MissingValueError
+	CodeRequired                             // RequiredError
+	CodeInvalidValue                         // InvalidValueError
+	CodeChoice                               // ChoiceError
+	CodeConflict                             // ConflictError
+	CodeValidation                           // ValidationError
+)
+
+// UnknownArgumentError will be returned when user input matches no argument
+type UnknownArgumentError struct {
+	Path        string   // command path of the parser, like 'tool deploy'
+	Token       string   // the unrecognized user input
+	Suggestions []string // similar entries to the input, like '--name'
+	tips        []string // suggestions with help message
+}
+
+func (e UnknownArgumentError) Error() string {
+	if len(e.tips) > 0 {
+		return fmt.Sprintf("unrecognized arguments: %s\ndo you mean?: %s", e.Token, strings.Join(e.tips, "\nor "))
+	}
+	return fmt.Sprintf("unrecognized arguments: %s", e.Token)
+}
+
+// format the error message with suggestions colored
+func (e UnknownArgumentError) format(schema *ColorSchema) string {
+	if len(e.tips) == 0 {
+		return e.Error()
+	}
+	var tips []string
+	for i, tip := range e.tips { // each tip starts with its suggestion
+		tips = append(tips, wrapperColor(e.Suggestions[i], schema.Suggestion)+strings.TrimPrefix(tip, e.Suggestions[i]))
+	}
+	return fmt.Sprintf("unrecognized arguments: %s\ndo you mean?: %s", e.Token, strings.Join(tips, "\nor "))
+}
+
+func (e UnknownArgumentError) Code() ErrorCode {
+	return CodeUnknownArgument
+}
+
+// MissingValueError will be returned when an argument is given without its value
+type MissingValueError struct {
+	Path     string // command path of the parser
+	Argument string // entries of the argument, like '--name/-n'
+}
+
+func (e MissingValueError) Error() string {
+	return fmt.Sprintf("argument %s expect argument", e.Argument)
+}
+
+func (e MissingValueError) Code() ErrorCode {
+	return CodeMissingValue
+}
+
+// RequiredError will be returned when a required argument is not given
+type RequiredError struct {
+	Path     string // command path of the parser
+	Argument string // meta name of the argument
+}
+
+func (e RequiredError) Error() string {
+	return fmt.Sprintf("%s is required", e.Argument)
+}
+
+func (e RequiredError) Code() ErrorCode {
+	return CodeRequired
+}
+
+// InvalidValueError will be returned when the input can't be converted to the argument type,
+// or it's refused by Option.Validate or Option.Formatter, which is kept as Err
+type InvalidValueError struct {
+	Path     string // command path of the parser
+	Argument string // identifier of the argument
+	Token    string // the invalid user input
+	Type     string // expected value type, like int, float
+	Err      error  // error from Option.Validate or Option.Formatter
+}
+
+func (e InvalidValueError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("invalid %s value: %s", e.Type, e.Token)
+}
+
+func (e InvalidValueError) Unwrap() error {
+	return e.Err
+}
+
+func (e InvalidValueError) Code() ErrorCode {
+	return CodeInvalidValue
+}
+
+// ChoiceError will be returned when the input is not one of Option.Choices
+type ChoiceError struct {
+	Path     string        // command path of the parser
+	Argument string        // identifier of the argument
+	Token    string        // the refused value
+	Choices  []interface{} // available choices
+}
+
+func (e ChoiceError) Error() string {
+	var values []interface{}
+	for _, c := range e.Choices {
+		values = append(values, choiceValue(c))
+	}
+	return fmt.Sprintf("args must be one|some of %+v", values)
+}
+
+func (e ChoiceError) Code() ErrorCode {
+	return CodeChoice
+}
+
+// ConflictError will be returned when an argument or a sub command is registered twice
+type ConflictError struct {
+	Path        string // command path of the parser
+	Kind        string // one of 'entry', 'positional', 'sub command'
+	Name        string // conflict name, like '--name', 'NAME', 'deploy'
+	Description string // help message of the registered argument, or description of the registered sub command
+}
+
+func (e ConflictError) Error() string {
+	if e.Kind == "sub command" {
+		return fmt.Sprintf("conflict sub command for '%s', desc: '%s'", e.Name, e.Description)
+	}
+	return fmt.Sprintf("conflict %s for '%s', say: '%s'", e.Kind, e.Name, e.Description)
+}
+
+func (e ConflictError) Code() ErrorCode {
+	return CodeConflict
+}
+
+// ValidationError will be returned when a validator added by Parser.AddValidator fails
+type ValidationError struct {
+	Path string // command path of the parser running the validator
+	Err  error  // error returned by the validator
+}
+
+func (e ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func (e ValidationError) Code() ErrorCode {
+	return CodeValidation
+}
+
+// ParseError wraps the error returned by Parse with the parser producing it
+//
+// Error() returns the same message as Err, and it's prefixed with usage & suffixed with help hint
+// if ParserConfig.ShowUsageOnError of the parser is set
+type ParseError struct {
+	Parser *Parser // the parser producing the error, may be a sub command parser
+	Usage  string  // usage of the parser
+	Err    error
+}
+
+func (e ParseError) Error() string {
+	if e.Parser == nil || !e.Parser.config.ShowUsageOnError {
+		return e.Err.Error()
+	}
+	result := fmt.Sprintf("%s\n%s", e.Usage, e.Err.Error())
+	if e.Parser.showHelp != nil {
+		result += fmt.Sprintf("\nsee '%s --help'", strings.Join(e.Parser.commandPath(), " "))
+	}
+	return result
+}
+
+func (e ParseError) Unwrap() error {
+	return e.Err
+}
+
+// bindPath fill command path into typed errors, errors with path are kept
+func bindPath(e error, path string) error {
+	switch err := e.(type) {
+	case UnknownArgumentError:
+		if err.Path == "" {
+			err.Path = path
+		}
+		return err
+	case MissingValueError:
+		if err.Path == "" {
+			err.Path = path
+		}
+		return err
+	case RequiredError:
+		if err.Path == "" {
+			err.Path = path
+		}
+		return err
+	case InvalidValueError:
+		if err.Path == "" {
+			err.Path = path
+		}
+		return err
+	case ChoiceError:
+		if err.Path == "" {
+			err.Path = path
+		}
+		return err
+	case ConflictError:
+		if err.Path == "" {
+			err.Path = path
+		}
+		return err
+	case ValidationError:
+		if err.Path == "" {
+			err.Path = path
+		}
+		return err
+	}
+	return e
+}
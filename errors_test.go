@@ -0,0 +1,91 @@
+package argparse
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestTypedErrors(t *testing.T) {
+	p := NewParser("tool", "", nil)
+	p.String("n", "name", &Option{Help: "your name"})
+	sub := p.AddCommand("deploy", "", nil)
+	sub.Int("r", "replicas", &Option{Choices: []interface{}{1, 2}})
+	sub.String("", "zone", &Option{Required: true})
+	sub.String("", "tag", &Option{Validate: func(arg string) error {
+		return fmt.Errorf("bad tag")
+	}})
+
+	var unknown UnknownArgumentError
+	if e := p.Parse([]string{"--nam"}); !errors.As(e, &unknown) || unknown.Token != "--nam" ||
+		unknown.Path != "tool" || len(unknown.Suggestions) != 1 || unknown.Suggestions[0] != "--name" ||
+		unknown.Code() != CodeUnknownArgument {
+		t.Error("failed to return unknown argument error")
+		return
+	}
+	var missing MissingValueError
+	if e := p.Parse([]string{"--name"}); !errors.As(e, &missing) || missing.Argument != "--name/-n" {
+		t.Error("failed to return missing value error")
+		return
+	}
+	var invalid InvalidValueError
+	if e := p.Parse([]string{"deploy", "-r", "x"}); !errors.As(e, &invalid) || invalid.Path != "tool deploy" ||
+		invalid.Token != "x" || invalid.Type != "int" || e.Error() != "invalid int value: x" {
+		t.Error("failed to return invalid value error")
+		return
+	}
+	if e := p.Parse([]string{"deploy", "--tag", "x"}); !errors.As(e, &invalid) || invalid.Argument != "tag" ||
+		invalid.Err == nil || e.Error() != "bad tag" {
+		t.Error("failed to wrap validate error")
+		return
+	}
+	var choice ChoiceError
+	if e := p.Parse([]string{"deploy", "-r", "3"}); !errors.As(e, &choice) || choice.Token != "3" ||
+		choice.Argument != "replicas" || e.Error() != "args must be one|some of [1 2]" {
+		t.Error("failed to return choice error")
+		return
+	}
+	var required RequiredError
+	if e := p.Parse([]string{"deploy", "-r", "1"}); !errors.As(e, &required) || required.Argument != "ZONE" ||
+		required.Path != "tool deploy" {
+		t.Error("failed to return required error")
+		return
+	}
+	var conflict ConflictError
+	if e := sub.registerParser(NewParser("deploy", "", nil)); e != nil {
+		t.Error("sub command should not conflict")
+		return
+	}
+	if e := p.registerParser(NewParser("deploy", "", nil)); !errors.As(e, &conflict) ||
+		conflict.Kind != "sub command" || e.Error() != "conflict sub command for 'deploy', desc: ''" {
+		t.Error("failed to return conflict error")
+		return
+	}
+}
+
+func TestParseError(t *testing.T) {
+	p := NewParser("tool", "", nil)
+	deploy := p.AddCommand("deploy", "", &ParserConfig{ShowUsageOnError: true})
+	deploy.Int("r", "replicas", nil)
+	p.Int("", "port", nil)
+
+	var failure ParseError
+	e := p.Parse([]string{"deploy", "--replicas", "abc"})
+	if !errors.As(e, &failure) || failure.Parser != deploy || failure.Usage != "usage: tool deploy [--help] [--replicas REPLICAS]" {
+		t.Error("failed to carry the parser producing error")
+		return
+	}
+	if e.Error() != "usage: tool deploy [--help] [--replicas REPLICAS]\ninvalid int value: abc\nsee 'tool deploy --help'" {
+		t.Error("failed to show usage on error")
+		return
+	}
+	e = p.Parse([]string{"--port", "abc"})
+	if !errors.As(e, &failure) || failure.Parser != p || e.Error() != "invalid int value: abc" {
+		t.Error("usage should not show")
+		return
+	}
+	if e := p.Parse([]string{"-h"}); e != BreakAfterHelpError {
+		t.Error("break should not be wrapped")
+		return
+	}
+}
@@ -0,0 +1,73 @@
+package argparse
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// defaultExitCode is the exit code for parse errors, same as python argparse
+const defaultExitCode = 2
+
+// exitCode decide exit code for the error by ParserConfig.ExitCodes
+func (p *Parser) exitCode(e error) int {
+	var coded interface{ Code() ErrorCode }
+	if errors.As(e, &coded) {
+		if code, exist := p.config.ExitCodes[coded.Code()]; exist {
+			return code
+		}
+	}
+	return defaultExitCode
+}
+
+// ParseOrExit parse args like Parse, but exit the program when it's not going to continue
+//
+// exit with 0 after help, version or shell script showed. for other errors, usage of the relevant
+// command & the error are printed to ParserConfig.Stderr, then exit with the code decided by
+// ParserConfig.ExitCodes, default to 2. ParserConfig.Exit can replace os.Exit for test
+func (p *Parser) ParseOrExit(args []string) {
+	if args == nil {
+		args = os.Args[1:]
+	}
+	e := p.Parse(args)
+	if e == nil {
+		return
+	}
+	exit := os.Exit
+	if p.config.Exit != nil {
+		exit = p.config.Exit
+	}
+	switch e.(type) {
+	case BreakAfterHelp, BreakAfterShellScript, BreakAfterVersion:
+		exit(0)
+		return
+	}
+	fmt.Fprintln(p.failure(e).Parser.stderr(), p.FormatError(e))
+	exit(p.exitCode(e))
+}
+
+// failure returns the ParseError carried by e, or wrap e with current parser
+func (p *Parser) failure(e error) ParseError {
+	failure := ParseError{Parser: p, Usage: strings.TrimRight(p.formatUsage(), " "), Err: e}
+	errors.As(e, &failure)
+	return failure
+}
+
+// FormatError format the error returned by Parse like 'usage: ...\n<command>: error: <message>',
+// usage is of the parser producing the error. it's colored by ColorSchema Usage, Error & Suggestion
+// if ParserConfig.WithColor is set & stderr supports color
+func (p *Parser) FormatError(e error) string {
+	if e == nil {
+		return ""
+	}
+	failure := p.failure(e)
+	schema := failure.Parser.colorSchema(failure.Parser.stderr())
+	message := failure.Err.Error()
+	var unknown UnknownArgumentError
+	if errors.As(failure.Err, &unknown) {
+		message = unknown.format(schema)
+	}
+	return fmt.Sprintf("%s\n%s %s", wrapperColor(failure.Usage, schema.Usage),
+		wrapperColor(strings.Join(failure.Parser.commandPath(), " ")+": error:", schema.Error), message)
+}
@@ -0,0 +1,99 @@
+package argparse
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseOrExit(t *testing.T) {
+	var out, errOut strings.Builder
+	code := -1
+	config := &ParserConfig{
+		Stdout:    &out,
+		Stderr:    &errOut,
+		Exit:      func(c int) { code = c },
+		ExitCodes: map[ErrorCode]int{CodeRequired: 3},
+	}
+	p := NewParser("tool", "", config)
+	deploy := p.AddCommand("deploy", "", nil)
+	deploy.Int("r", "replicas", nil)
+	deploy.String("", "zone", &Option{Required: true})
+	p.String("n", "name", &Option{Action: func(args []string) error {
+		return fmt.Errorf("no name")
+	}})
+
+	p.ParseOrExit([]string{"deploy", "-r", "x"})
+	if code != 2 {
+		t.Error("failed to exit with default code")
+		return
+	}
+	if errOut.String() != "usage: tool deploy [--help] [--replicas REPLICAS] --zone ZONE\ntool deploy: error: invalid int value: x\n" {
+		t.Error("failed to print error with sub command usage")
+		return
+	}
+	errOut.Reset()
+	p.ParseOrExit([]string{"deploy", "-r", "1"})
+	if code != 3 || !strings.HasSuffix(errOut.String(), "tool deploy: error: ZONE is required\n") {
+		t.Error("failed to exit with given code")
+		return
+	}
+	errOut.Reset()
+	p.ParseOrExit([]string{"-n", "x"})
+	if code != 2 || !strings.HasPrefix(errOut.String(), "usage: tool <cmd> ") ||
+		!strings.HasSuffix(errOut.String(), "tool: error: no name\n") {
+		t.Error("failed to handle untyped error")
+		return
+	}
+
+	code = -1
+	p = NewParser("tool", "", config)
+	p.ParseOrExit([]string{"-h"})
+	if code != 0 || !strings.HasPrefix(out.String(), "usage: tool") {
+		t.Error("failed to exit after help")
+		return
+	}
+
+	code = -1
+	p = NewParser("tool", "", config)
+	p.Flag("v", "", nil)
+	p.ParseOrExit([]string{"-v"})
+	if code != -1 {
+		t.Error("should not exit")
+		return
+	}
+}
+
+func TestFormatError(t *testing.T) {
+	var errOut strings.Builder
+	p := NewParser("tool", "", &ParserConfig{Stderr: &errOut, WithColor: true, EnsureColor: true})
+	p.String("", "name", &Option{Help: "your name"})
+	if p.FormatError(nil) != "" {
+		t.Error("nil error should be empty")
+		return
+	}
+	e := p.Parse([]string{"--nam", "x"})
+	message := p.FormatError(e)
+	if !strings.Contains(message, wrapperColor("tool: error:", DefaultColor.Error)+" unrecognized arguments: --nam") {
+		t.Error("failed to color error label")
+		return
+	}
+	if !strings.Contains(message, "do you mean?: "+wrapperColor("--name", DefaultColor.Suggestion)+" (your name)") {
+		t.Error("failed to color suggestion")
+		return
+	}
+	p = NewParser("tool", "", &ParserConfig{Stderr: &errOut, WithColor: true, EnsureColor: true})
+	p.Int("", "old", &Option{Deprecated: "no more"})
+	_ = p.Parse([]string{"--old", "1"})
+	if !strings.HasPrefix(errOut.String(), wrapperColor("warning:", DefaultColor.Warning)+" --old is deprecated") {
+		t.Error("failed to color warning")
+		return
+	}
+	p = NewParser("tool", "", nil)
+	p.String("", "name", nil)
+	e = p.Parse([]string{"--nam", "x"})
+	if p.FormatError(e) != "usage: tool [--help] [--name NAME]\ntool: error: unrecognized arguments: --nam\ndo you mean?: --name" {
+		t.Error("error should have no color without WithColor")
+		return
+	}
+}
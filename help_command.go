@@ -0,0 +1,36 @@
+package argparse
+
+// addHelpCommand register 'help' sub command to show help message of the given command path
+func (p *Parser) addHelpCommand() {
+	p.helpCommand = p.AddCommand("help", "show help message of the command",
+		&ParserConfig{DisableDefaultShowHelp: true})
+	p.helpTarget = p.helpCommand.Strings("", "command", &Option{Positional: true,
+		Help: "command path, like 'cmd sub-cmd', show all commands if not given"})
+}
+
+// runHelpCommand parse args for 'help' sub command, then show help message of the command path
+func (p *Parser) runHelpCommand(args []string) error {
+	*p.helpTarget = nil
+	if e := p.helpCommand.Parse(args); e != nil {
+		return e
+	}
+	parser := p
+	for _, name := range *p.helpTarget {
+		sub, match := parser.subParserMap[name]
+		if !match {
+			var candidates []string
+			for _, c := range parser.subParser {
+				candidates = append(candidates, c.name)
+			}
+			e := UnknownArgumentError{Token: name}
+			for _, m := range decideMatch(name, candidates) {
+				e.Suggestions = append(e.Suggestions, m)
+				e.tips = append(e.tips, m)
+			}
+			return e
+		}
+		parser = sub
+	}
+	parser.PrintHelp()
+	return BreakAfterHelpError
+}
@@ -0,0 +1,43 @@
+package argparse
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHelpCommand(t *testing.T) {
+	var out strings.Builder
+	p := NewParser("tool", "this is a tool", &ParserConfig{AddHelpCommand: true, Stdout: &out})
+	deploy := p.AddCommand("deploy", "deploy the app", nil)
+	deploy.AddCommand("scale", "scale the app", nil)
+	p.AddCommand("status", "", nil)
+
+	if e := p.Parse([]string{"help"}); e != BreakAfterHelpError || !strings.HasPrefix(out.String(), "usage: tool <cmd>") ||
+		!strings.Contains(out.String(), "deploy the app") {
+		t.Error("failed to list all commands")
+		return
+	}
+	out.Reset()
+	if e := p.Parse([]string{"help", "deploy", "scale"}); e != BreakAfterHelpError ||
+		!strings.HasPrefix(out.String(), "usage: tool deploy scale") {
+		t.Error("failed to show help of command path")
+		return
+	}
+	out.Reset()
+	if e := p.Parse([]string{"help", "deploy"}); e != BreakAfterHelpError ||
+		!strings.HasPrefix(out.String(), "usage: tool deploy <cmd>") {
+		t.Error("help target should be reset")
+		return
+	}
+	var unknown UnknownArgumentError
+	if e := p.Parse([]string{"help", "deploy", "scal"}); !errors.As(e, &unknown) || unknown.Suggestions[0] != "scale" ||
+		e.Error() != "unrecognized arguments: scal\ndo you mean?: scale" {
+		t.Error("failed to suggest command")
+		return
+	}
+	if _, exist := deploy.subParserMap["help"]; exist {
+		t.Error("help command should only be added to the root parser")
+		return
+	}
+}
@@ -0,0 +1,282 @@
+package argparse
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DefaultHelpTemplate is the default layout of help message:
+// usage, description, sub commands, positionals, options, argument groups, examples & epilog
+const DefaultHelpTemplate = `{{define "section"}}
+{{- if .Rows}}
+
+{{.Title}}
+{{- range .Rows}}
+{{.Line}}
+{{- end}}
+{{- end}}
+{{- end}}
+{{- .Usage}}
+{{- if .Description}}
+
+{{.Description}}
+{{- end}}
+{{- range .Commands}}{{template "section" .}}{{end}}
+{{- template "section" .Positionals}}
+{{- template "section" .Options}}
+{{- range .Groups}}{{template "section" .}}{{end}}
+{{- template "section" .Examples}}
+{{- if .EpiLog}}
+
+{{.EpiLog}}
+{{- end}}`
+
+// DefaultUsageTemplate is the default layout of usage line, like 'usage: tool <cmd> [--help] NAME '
+const DefaultUsageTemplate = `usage: {{join .Path " "}} {{if .HasCommands}}<cmd> {{end}}
+{{- range .Options}}{{.}} {{end}}
+{{- range .Positionals}}{{.}} {{end}}`
+
+// HelpRow is a sub command or an argument in help message
+type HelpRow struct {
+	Header   string // colored header, like '--name NAME, -n NAME' or sub command name
+	Help     string // help info, with hint if ParserConfig.WithHint is set
+	Line     string // header & help info aligned & wrapped
+	Type     string // value type of the argument, like string, []int. empty for sub command
+	Default  string // default value of the argument
+	Required bool   // the argument is required
+}
+
+// HelpSection is a titled list of rows in help message
+type HelpSection struct {
+	Name  string    // plain title, like 'options', argument group or sub command category
+	Title string    // colored title, like 'options:'
+	Rows  []HelpRow // visible rows, the section is usually skipped if it's empty
+}
+
+// HelpData is the data model to execute ParserConfig.HelpTemplate
+type HelpData struct {
+	Parser       *Parser
+	Schema       *ColorSchema  // color schema for the template function 'color'
+	Path         []string      // command path from the root parser
+	Usage        string        // colored usage line
+	Description  string        // colored & wrapped description
+	Commands     []HelpSection // sub commands by category, commands without category come first
+	Positionals  HelpSection
+	Options      HelpSection
+	Groups       []HelpSection // argument groups in order
+	Examples     HelpSection   // examples of the command, Header is the command line & Help is its description
+	EpiLog       string        // colored & wrapped epilog
+	Width        int           // width of help message
+	HeaderLength int           // width of header column, help info starts after it
+}
+
+// UsageData is the data model to execute ParserConfig.UsageTemplate
+type UsageData struct {
+	Parser      *Parser
+	Path        []string // command path from the root parser
+	HasCommands bool     // the parser has sub commands
+	Options     []string // usage of visible optional arguments, like '[--name NAME]'
+	Positionals []string // usage of visible positional arguments, like 'NAME'
+}
+
+// templateFuncs are functions usable in help & usage templates
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"color": wrapperColor,
+	"wrap": func(width int, content string) string {
+		return strings.Join(wrapText(content, width, width), "\n")
+	},
+	"pad": func(width int, content string) string {
+		if w := displayWidth(content); w < width {
+			return content + strings.Repeat(" ", width-w)
+		}
+		return content
+	},
+}
+
+var defaultHelpTemplate = template.Must(template.New("help").Funcs(templateFuncs).Parse(DefaultHelpTemplate))
+var defaultUsageTemplate = template.Must(template.New("usage").Funcs(templateFuncs).Parse(DefaultUsageTemplate))
+
+// loadTemplates parse help & usage templates given by ParserConfig
+func (p *Parser) loadTemplates() error {
+	if p.config.HelpTemplate != "" {
+		t, e := template.New("help").Funcs(templateFuncs).Parse(p.config.HelpTemplate)
+		if e != nil {
+			return e
+		}
+		p.helpTemplate = t
+	}
+	if p.config.UsageTemplate != "" {
+		t, e := template.New("usage").Funcs(templateFuncs).Parse(p.config.UsageTemplate)
+		if e != nil {
+			return e
+		}
+		p.usageTemplate = t
+	}
+	return nil
+}
+
+// renderTemplate render the template with data, error is wrapped with the template name
+func renderTemplate(t *template.Template, data interface{}) (string, error) {
+	var result strings.Builder
+	if e := t.Execute(&result, data); e != nil {
+		return "", fmt.Errorf("failed to render %s: %w", t.Name(), e)
+	}
+	return result.String(), nil
+}
+
+// executeTemplate render the template, the error is rendered instead if failed, Parser.FormatHelpE returns it
+func executeTemplate(t *template.Template, data interface{}) string {
+	result, e := renderTemplate(t, data)
+	if e != nil {
+		return e.Error()
+	}
+	return result
+}
+
+func (a *arg) helpRow(schema *ColorSchema, withHint bool, headerLength, width int, withBreak bool) HelpRow {
+	help := a.Help
+	if withHint && !a.NoHint {
+		help = a.formatHelpWithExtraInfo()
+	}
+	size, header := a.formatHelpHeader(schema.Argument, schema.Meta)
+	line := formatHelpRow(header, help, size, headerLength, width, withBreak)
+	if a.hasChoiceHelp() { // choices with help follow the argument as a sub table
+		line += "\n" + a.formatChoiceTable(schema.Meta, headerLength, width)
+	}
+	return HelpRow{
+		Header:   header,
+		Help:     help,
+		Line:     line,
+		Type:     a.getTypeName(),
+		Default:  a.Default,
+		Required: a.Required,
+	}
+}
+
+// helpData collect data to render help message
+func (p *Parser) helpData(schema *ColorSchema) HelpData {
+	width := decideTerminalWidth(p.stdout(), p.config.Width, p.config.DefaultWidth)
+	data := HelpData{
+		Parser: p,
+		Schema: schema,
+		Path:   p.commandPath(),
+		Usage:  wrapperColor(p.formatUsage(), schema.Usage),
+		Width:  width,
+	}
+	if p.description != "" {
+		data.Description = wrapperColor(strings.Join(wrapText(p.description, width, width), "\n"), schema.Description)
+	}
+	if p.config.EpiLog != "" {
+		data.EpiLog = wrapperColor(strings.Join(wrapText(p.config.EpiLog, width, width), "\n"), schema.Epilog)
+	}
+	// calculate header length
+	headerLength := 10 // here set minimum header length, the code after will find the max length of headers
+	categories, commands := p.listCommands()
+	for _, entries := range commands {
+		for _, c := range entries {
+			l := len(c.parser.name) + c.depth*2
+			if l > headerLength {
+				headerLength = l
+			}
+		}
+	}
+	for _, arg := range p.positionArgs {
+		l, _ := arg.formatHelpHeader(schema.Argument, schema.Meta)
+		if l > headerLength {
+			headerLength = l
+		}
+	}
+	for _, arg := range p.entries {
+		l, _ := arg.formatHelpHeader(schema.Argument, schema.Meta)
+		if l > headerLength {
+			headerLength = l
+		}
+	}
+	headerLength += 4 // 2 space padding around header, before & after
+	helpBreak := false
+	if p.config.MaxHeaderLength > 0 {
+		headerLength = p.config.MaxHeaderLength
+		helpBreak = true
+	}
+	data.HeaderLength = headerLength
+
+	// sub command
+	for _, category := range categories {
+		name := category
+		if name == "" {
+			name = "commands"
+		}
+		section := HelpSection{Name: name, Title: wrapperColor(name+":", schema.GroupTitle)}
+		for _, c := range commands[category] {
+			indent := strings.Repeat("  ", c.depth)
+			header := indent + wrapperColor(c.parser.name, schema.Command)
+			section.Rows = append(section.Rows, HelpRow{
+				Header: header,
+				Help:   c.parser.description,
+				Line: formatHelpRow(header, c.parser.description,
+					len(indent)+displayWidth(c.parser.name), headerLength, width, helpBreak),
+			})
+		}
+		data.Commands = append(data.Commands, section)
+	}
+	withHint := p.config.WithHint
+	data.Positionals = HelpSection{Name: "positionals", Title: wrapperColor("positionals:", schema.GroupTitle)}
+	for _, arg := range p.visiblePositionals() {
+		data.Positionals.Rows = append(data.Positionals.Rows, arg.helpRow(schema, withHint, headerLength, width, helpBreak))
+	}
+	data.Options = HelpSection{Name: "options", Title: wrapperColor("options:", schema.GroupTitle)}
+	for _, arg := range p.visibleOptions() {
+		data.Options.Rows = append(data.Options.Rows, arg.helpRow(schema, withHint, headerLength, width, helpBreak))
+	}
+	// argument groups
+	for _, group := range p.entryGroupOrder {
+		section := HelpSection{Name: group, Title: wrapperColor(group+":", schema.GroupTitle)}
+		for _, arg := range p.visibleGroupEntries(group) {
+			section.Rows = append(section.Rows, arg.helpRow(schema, withHint, headerLength, width, helpBreak))
+		}
+		data.Groups = append(data.Groups, section)
+	}
+	data.Examples = HelpSection{Name: "examples", Title: wrapperColor("examples:", schema.GroupTitle)}
+	for _, example := range p.examples() {
+		line := "  " + wrapperColor(example.Command, schema.Command) // command line is never wrapped
+		if example.Description != "" {
+			for _, l := range wrapText(example.Description, width-4, width-4) {
+				line += "\n" + strings.TrimRight("    "+l, " ")
+			}
+		}
+		data.Examples.Rows = append(data.Examples.Rows, HelpRow{
+			Header: wrapperColor(example.Command, schema.Command),
+			Help:   example.Description,
+			Line:   line,
+		})
+	}
+	return data
+}
+
+// usageData collect data to render usage line
+func (p *Parser) usageData() UsageData {
+	data := UsageData{
+		Parser:      p,
+		Path:        p.commandPath(),
+		HasCommands: len(p.subParser) > 0,
+	}
+	parsed := make(map[string]bool)
+	for _, arg := range p.entries {
+		identifier := arg.getIdentifier()
+		if _, exist := parsed[identifier]; exist {
+			continue
+		}
+		parsed[identifier] = true
+		if argUsage := arg.formatUsage(); argUsage != "" {
+			data.Options = append(data.Options, strings.TrimRight(argUsage, " "))
+		}
+	}
+	for _, arg := range p.positionArgs {
+		if argUsage := arg.formatUsage(); argUsage != "" {
+			data.Positionals = append(data.Positionals, strings.TrimRight(argUsage, " "))
+		}
+	}
+	return data
+}
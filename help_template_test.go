@@ -0,0 +1,98 @@
+package argparse
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHelpTemplate(t *testing.T) {
+	p := NewParser("tool", "", &ParserConfig{
+		HelpTemplate: `{{.Usage}}
+{{- with .Options}}
+
+Flags:
+{{- range .Rows}}
+{{.Line}}
+{{- end}}
+{{- end}}
+{{- range .Positionals.Rows}}
+{{pad 8 .Header}}{{.Type}}
+{{- end}}`,
+	})
+	p.String("", "name", &Option{Help: "your name"})
+	p.Int("", "count", &Option{Positional: true})
+	help := p.FormatHelp()
+	if !strings.Contains(help, "\n\nFlags:\n  --help, -h") {
+		t.Error("failed to render custom heading")
+		return
+	}
+	if !strings.HasSuffix(help, "\nCOUNT   int") {
+		t.Error("failed to render custom columns")
+		return
+	}
+	if strings.Contains(help, "options:") {
+		t.Error("default layout should be replaced")
+		return
+	}
+	sub := p.AddCommand("sub", "", nil)
+	if !strings.Contains(sub.FormatHelp(), "Flags:") {
+		t.Error("sub command sharing config should share the template")
+		return
+	}
+	sub = p.AddCommand("own", "", &ParserConfig{})
+	if !strings.Contains(sub.FormatHelp(), "options:") {
+		t.Error("sub command with its own config should use default template")
+		return
+	}
+}
+
+func TestUsageTemplate(t *testing.T) {
+	p := NewParser("tool", "", &ParserConfig{
+		UsageTemplate: `Usage: {{join .Path " "}}{{if .Options}} [options]{{end}}{{range .Positionals}} {{.}}{{end}}`,
+	})
+	p.String("", "name", nil)
+	p.String("", "file", &Option{Positional: true, Required: true})
+	if usage := p.formatUsage(); usage != "Usage: tool [options] FILE" {
+		t.Error("failed to render usage template: " + usage)
+		return
+	}
+	if !strings.HasPrefix(p.FormatHelp(), "Usage: tool [options] FILE\n\n") {
+		t.Error("help should use usage template")
+		return
+	}
+}
+
+func TestInvalidTemplate(t *testing.T) {
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("invalid template should panic")
+			}
+		}()
+		NewParser("", "", &ParserConfig{HelpTemplate: "{{.Usage"})
+	}()
+	b := NewParser("", "", nil).Builder()
+	b.AddCommand("sub", "", &ParserConfig{UsageTemplate: "{{end}}"})
+	if e := b.Build(); e == nil || !strings.Contains(e.Error(), "command 'sub'") {
+		t.Error("invalid sub command template should be reported")
+		return
+	}
+	p := NewParser("", "", &ParserConfig{HelpTemplate: "{{.Unknown}}"})
+	if !strings.HasPrefix(p.FormatHelp(), "failed to render help") {
+		t.Error("render error should be shown")
+		return
+	}
+	if _, e := p.FormatHelpE(); e == nil || !strings.HasPrefix(e.Error(), "failed to render help") {
+		t.Error("render error should be returned")
+		return
+	}
+	p = NewParser("", "", &ParserConfig{UsageTemplate: "{{.Unknown}}"})
+	if _, e := p.FormatHelpE(); e == nil || !strings.HasPrefix(e.Error(), "failed to render usage") {
+		t.Error("usage render error should be returned")
+		return
+	}
+	if help, e := NewParser("", "", nil).FormatHelpE(); e != nil || help != NewParser("", "", nil).FormatHelp() {
+		t.Error("failed to format help")
+		return
+	}
+}
@@ -0,0 +1,146 @@
+package argparse
+
+// ArgumentKind tells how an argument takes its input
+type ArgumentKind int
+
+const (
+	OptionalArgument   ArgumentKind = iota // like --name NAME
+	FlagArgument                           // like --verbose, takes no input
+	PositionalArgument                     // like NAME
+)
+
+func (k ArgumentKind) String() string {
+	switch k {
+	case FlagArgument:
+		return "flag"
+	case PositionalArgument:
+		return "positional"
+	}
+	return "optional"
+}
+
+// ArgumentInfo is the read-only view of a registered argument
+type ArgumentInfo struct {
+	Short    string       // short name without prefix
+	Full     string       // full name without prefix
+	Names    []string     // entries to match in user input, like --name, -n. empty for positional
+	Kind     ArgumentKind // how the argument takes input
+	Type     string       // value type, like flag, string, []int
+	Option   Option       // options given when creating the argument
+	Assigned bool         // whether the argument is parsed, default value counts too
+	Source   ValueSource  // where the parsed value comes from
+}
+
+func (a *arg) info() ArgumentInfo {
+	kind := OptionalArgument
+	if a.isFlag {
+		kind = FlagArgument
+	} else if a.Positional {
+		kind = PositionalArgument
+	}
+	option := a.Option // copy slices & pointers, so that the view can't change the parser
+	option.Choices = append([]interface{}(nil), a.Choices...)
+	option.Aliases = append([]string(nil), a.Aliases...)
+	option.HiddenAliases = append([]string(nil), a.HiddenAliases...)
+	option.BindParsers = append([]*Parser(nil), a.BindParsers...)
+	if a.Min != nil {
+		option.Min = Bound(*a.Min)
+	}
+	if a.Max != nil {
+		option.Max = Bound(*a.Max)
+	}
+	return ArgumentInfo{
+		Short:    a.short,
+		Full:     a.full,
+		Names:    a.getWatchers(),
+		Kind:     kind,
+		Type:     a.getTypeName(),
+		Option:   option,
+		Assigned: a.assigned,
+		Source:   a.source,
+	}
+}
+
+// allArguments returns each registered argument once, optional arguments first, then positionals in order
+func (p *Parser) allArguments() []*arg {
+	var result []*arg
+	parsed := make(map[*arg]bool)
+	for _, a := range p.entries {
+		if parsed[a] {
+			continue
+		}
+		parsed[a] = true
+		result = append(result, a)
+	}
+	return append(result, p.positionArgs...)
+}
+
+// findArgument looks up an argument by its name, like 'port', '--port', '-p' or meta name of a positional
+func (p *Parser) findArgument(name string) *arg {
+	for _, key := range []string{name, fullPrefix + name, shortPrefix + name} {
+		if a, ok := p.entryMap[key]; ok {
+			return a
+		}
+	}
+	if a, ok := p.positionalPool[name]; ok {
+		return a
+	}
+	for _, a := range p.positionArgs {
+		if a.full == name || a.short == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// IsSet tells whether the argument is explicitly given by user input, default value doesn't count
+//
+// name can be 'port', '--port', '-p' or meta name of a positional
+func (p *Parser) IsSet(name string) bool {
+	return p.Source(name) == SourceCommandLine
+}
+
+// Source tells where the parsed value of the argument comes from, SourceUnset for unknown argument
+func (p *Parser) Source(name string) ValueSource {
+	if a := p.findArgument(name); a != nil {
+		return a.source
+	}
+	return SourceUnset
+}
+
+// Name returns the name of the parser
+func (p *Parser) Name() string {
+	return p.name
+}
+
+// Parent returns the parser creating current parser by AddCommand, nil for the root parser
+func (p *Parser) Parent() *Parser {
+	return p.parent
+}
+
+// Commands returns sub command parsers in registration order
+func (p *Parser) Commands() []*Parser {
+	return append([]*Parser{}, p.subParser...)
+}
+
+// Arguments returns the view of registered arguments, optional arguments first, then positionals in order
+func (p *Parser) Arguments() []ArgumentInfo {
+	var result []ArgumentInfo
+	for _, a := range p.allArguments() {
+		result = append(result, a.info())
+	}
+	return result
+}
+
+// Walk visit the parser & all its sub commands depth first, stop at the first error returned by fn
+func (p *Parser) Walk(fn func(*Parser) error) error {
+	if e := fn(p); e != nil {
+		return e
+	}
+	for _, parser := range p.subParser {
+		if e := parser.Walk(fn); e != nil {
+			return e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package argparse
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestInspect(t *testing.T) {
+	p := NewParser("tool", "", nil)
+	p.Int("p", "port", &Option{Default: "8080", Choices: []interface{}{8080, 9090}, Max: Bound(65535), Aliases: []string{"listen"}})
+	p.Flag("v", "verbose", nil)
+	p.String("", "target", &Option{Positional: true})
+	deploy := p.AddCommand("deploy", "", nil)
+	scale := deploy.AddCommand("scale", "", nil)
+	p.AddCommand("status", "", nil)
+
+	if p.Name() != "tool" || p.Parent() != nil || scale.Parent() != deploy || len(p.Commands()) != 2 {
+		t.Error("failed to inspect parsers")
+		return
+	}
+	args := p.Arguments()
+	if len(args) != 4 {
+		t.Error("each argument should show once")
+		return
+	}
+	if args[1].Kind != OptionalArgument || args[1].Type != "int" || args[1].Option.Default != "8080" ||
+		strings.Join(args[1].Names, ",") != "--port,-p,--listen" {
+		t.Error("failed to inspect optional argument")
+		return
+	}
+	args[1].Option.Choices[0] = 1
+	args[1].Option.Aliases[0] = "bind"
+	*args[1].Option.Max = 1
+	if view := p.Arguments()[1].Option; view.Choices[0] != 8080 || view.Aliases[0] != "listen" || *view.Max != 65535 {
+		t.Error("argument view should not change the parser")
+		return
+	}
+	if args[2].Kind != FlagArgument || args[3].Kind != PositionalArgument || len(args[3].Names) != 0 {
+		t.Error("failed to tell argument kind")
+		return
+	}
+	if e := p.Parse([]string{"x"}); e != nil {
+		t.Error(e)
+		return
+	}
+	args = p.Arguments()
+	if !args[1].Assigned || args[2].Assigned || !args[3].Assigned {
+		t.Error("failed to tell assigned state")
+		return
+	}
+
+	var names []string
+	if e := p.Walk(func(parser *Parser) error {
+		names = append(names, parser.Name())
+		return nil
+	}); e != nil {
+		t.Error(e)
+		return
+	}
+	if strings.Join(names, ",") != "tool,deploy,scale,status" {
+		t.Error("failed to walk command tree")
+		return
+	}
+	if e := p.Walk(func(parser *Parser) error {
+		if parser == scale {
+			return fmt.Errorf("stop")
+		}
+		return nil
+	}); e == nil || e.Error() != "stop" {
+		t.Error("failed to stop walking")
+		return
+	}
+}
+
+func TestValueSource(t *testing.T) {
+	p := NewParser("", "", nil)
+	p.Int("p", "port", &Option{Default: "8080"})
+	p.Int("", "timeout", &Option{Default: "10"})
+	p.Flag("v", "verbose", nil)
+	p.Flag("", "color", &Option{Default: "true"})
+	p.String("", "dry", nil)
+	p.String("", "target", &Option{Positional: true, Meta: "DEST"})
+	if e := p.Parse([]string{"--timeout", "10", "-v", "x"}); e != nil {
+		t.Error(e)
+		return
+	}
+	if p.IsSet("port") || p.Source("--port") != SourceDefault || p.Source("p").String() != "default" ||
+		p.IsSet("color") || p.Source("color") != SourceDefault {
+		t.Error("default value is not set by user")
+		return
+	}
+	if !p.IsSet("timeout") || !p.IsSet("-v") || !p.IsSet("DEST") || !p.IsSet("target") {
+		t.Error("failed to tell values given by user")
+		return
+	}
+	if p.IsSet("dry") || p.Source("dry") != SourceUnset || p.Source("not-exist") != SourceUnset {
+		t.Error("argument should be unset")
+		return
+	}
+	if p.Arguments()[2].Source != SourceCommandLine {
+		t.Error("failed to inspect source")
+		return
+	}
+}
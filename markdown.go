@@ -0,0 +1,117 @@
+package argparse
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// markdownFileName decide the document file name of the parser, like 'tool_deploy.md'
+func (p *Parser) markdownFileName() string {
+	return strings.Join(p.commandPath(), "_") + ".md"
+}
+
+// escape content to fit in a markdown table cell
+func escapeMarkdownCell(content string) string {
+	content = strings.ReplaceAll(content, "|", "\\|")
+	return strings.ReplaceAll(content, "\n", "<br>")
+}
+
+func formatMarkdownArgTable(args []*arg) string {
+	rows := []string{
+		"| Argument | Type | Default | Choices | Required | Description |",
+		"| --- | --- | --- | --- | --- | --- |",
+	}
+	for _, a := range args {
+		_, header := a.formatHelpHeader(Color{}, Color{})
+		choices := ""
+		if len(a.Choices) > 0 {
+			choices = a.dumpChoices()
+		}
+		required := ""
+		if a.Required {
+			required = "yes"
+		}
+		defaultValue := ""
+		if a.Default != "" {
+			defaultValue = fmt.Sprintf("`%s`", a.Default)
+		}
+		description := a.Help
+		if limits := a.formatLimits(); len(limits) > 0 {
+			description = strings.TrimLeft(fmt.Sprintf("%s (%s)", description, strings.Join(limits, ", ")), " ")
+		}
+		rows = append(rows, fmt.Sprintf("| `%s` | %s | %s | %s | %s | %s |",
+			escapeMarkdownCell(header), a.getTypeName(), escapeMarkdownCell(defaultValue),
+			escapeMarkdownCell(choices), required, escapeMarkdownCell(description)))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// FormatMarkdown generate a markdown reference page for the parser
+//
+// the page contains usage, description, sub commands, positionals, options, argument groups & examples,
+// parent & sub commands are linked to pages named by GenerateMarkdownTree
+func (p *Parser) FormatMarkdown() string {
+	path := p.commandPath()
+	sections := []string{fmt.Sprintf("# %s", strings.Join(path, " "))}
+	if len(p.parentList) > 0 {
+		var links []string
+		for i, parent := range p.parentList {
+			links = append(links, fmt.Sprintf("[%s](%s.md)", parent, strings.Join(p.parentList[:i+1], "_")))
+		}
+		sections = append(sections, strings.Join(append(links, p.name), " > "))
+	}
+	if p.description != "" {
+		sections = append(sections, p.description)
+	}
+	sections = append(sections, fmt.Sprintf("## Usage\n\n```\n%s\n```", strings.TrimRight(p.formatUsage(), " ")))
+
+	if commands := p.visibleCommands(); len(commands) > 0 {
+		rows := []string{"| Command | Description |", "| --- | --- |"}
+		for _, parser := range commands {
+			rows = append(rows, fmt.Sprintf("| [%s](%s) | %s |", parser.name, parser.markdownFileName(),
+				escapeMarkdownCell(parser.description)))
+		}
+		sections = append(sections, "## Commands", strings.Join(rows, "\n"))
+	}
+	if positionals := p.visiblePositionals(); len(positionals) > 0 {
+		sections = append(sections, "## Positionals", formatMarkdownArgTable(positionals))
+	}
+	if options := p.visibleOptions(); len(options) > 0 {
+		sections = append(sections, "## Options", formatMarkdownArgTable(options))
+	}
+	for _, group := range p.entryGroupOrder {
+		if args := p.visibleGroupEntries(group); len(args) > 0 {
+			sections = append(sections, "## "+group, formatMarkdownArgTable(args))
+		}
+	}
+	if examples := p.examples(); len(examples) > 0 {
+		sections = append(sections, "## Examples")
+		for _, example := range examples {
+			if example.Description != "" {
+				sections = append(sections, example.Description)
+			}
+			sections = append(sections, fmt.Sprintf("```\n%s\n```", example.Command))
+		}
+	}
+	if p.config.EpiLog != "" {
+		sections = append(sections, p.config.EpiLog)
+	}
+	return strings.Join(sections, "\n\n") + "\n"
+}
+
+// GenerateMarkdownTree write markdown reference pages of the parser & all its sub commands to dir
+//
+// each page is named by its command path, like 'tool.md', 'tool_deploy.md'
+func (p *Parser) GenerateMarkdownTree(dir string) error {
+	if e := os.WriteFile(filepath.Join(dir, p.markdownFileName()), []byte(p.FormatMarkdown()), 0644); e != nil {
+		return e
+	}
+	for _, parser := range p.visibleCommands() {
+		if e := parser.GenerateMarkdownTree(dir); e != nil {
+			return e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package argparse
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatMarkdown(t *testing.T) {
+	p := NewParser("tool", "this is a tool", &ParserConfig{EpiLog: "more info"})
+	p.Int("p", "port", &Option{Default: "8080", Help: "listen port"})
+	p.String("", "mode", &Option{Choices: []interface{}{"a", "b"}, Required: true, Group: "advanced"})
+	p.String("", "secret", &Option{HideEntry: true})
+	p.String("", "target", &Option{Positional: true, Help: "a | b"})
+	deploy := p.AddCommand("deploy", "deploy the app", nil)
+	deploy.Flag("f", "force", nil)
+
+	doc := p.FormatMarkdown()
+	for _, expect := range []string{"# tool\n", "## Usage", "## Commands", "[deploy](tool_deploy.md)",
+		"## Positionals", "| `TARGET` | string |  |  |  | a \\| b |",
+		"## Options", "| `--port PORT, -p PORT` | int | `8080` |  |  | listen port |",
+		"## advanced", "| a, b | yes |", "more info"} {
+		if !strings.Contains(doc, expect) {
+			t.Errorf("markdown missing %q", expect)
+			return
+		}
+	}
+	if strings.Contains(doc, "secret") {
+		t.Error("hidden entry should not show")
+		return
+	}
+	if !strings.Contains(deploy.FormatMarkdown(), "[tool](tool.md) > deploy") {
+		t.Error("failed to link parent")
+		return
+	}
+
+	dir, e := os.MkdirTemp("", "argparse")
+	if e != nil {
+		t.Error(e)
+		return
+	}
+	defer os.RemoveAll(dir)
+	if e := p.GenerateMarkdownTree(dir); e != nil {
+		t.Error(e)
+		return
+	}
+	for _, name := range []string{"tool.md", "tool_deploy.md"} {
+		if _, e := os.Stat(filepath.Join(dir, name)); e != nil {
+			t.Errorf("failed to generate %s", name)
+			return
+		}
+	}
+}
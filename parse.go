@@ -2,9 +2,12 @@ package argparse
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path"
+	"sort"
 	"strings"
+	"text/template"
 )
 
 // Parser is the top level struct. Don't use it directly, use NewParser to create one
@@ -20,6 +23,11 @@ type Parser struct {
 
 	showHelp            *bool // flag to decide show help message
 	showShellCompletion *bool // flag to decide show shell completion
+	showHelpJSON        *bool // flag to decide show json description
+	showVersion         *bool // flag to decide show version
+
+	helpCommand *Parser   // the 'help' sub command
+	helpTarget  *[]string // command path given to 'help' sub command
 
 	entries        []*arg
 	entryMap       map[string]*arg
@@ -32,6 +40,12 @@ type Parser struct {
 	subParser    []*Parser
 	subParserMap map[string]*Parser
 	parentList   []string
+	parent       *Parser
+
+	validators []func(parser *Parser) error // cross-argument validators run after parse
+
+	helpTemplate  *template.Template // parsed ParserConfig.HelpTemplate
+	usageTemplate *template.Template // parsed ParserConfig.UsageTemplate
 }
 
 // ParserConfig is the only type to config `Parser`, programmers only need to use this type to control `Parser` action
@@ -45,16 +59,56 @@ type ParserConfig struct {
 
 	DefaultAction      func() // set default action to replace default help action
 	AddShellCompletion bool   // set true to register shell completion entry [--completion]
+	AddHelpJSON        bool   // set true to register hidden json description entry [--help-json]
 	WithHint           bool   // argument help message with argument default value hint
 	MaxHeaderLength    int    // max argument header length in help menu, help info will start at new line if argument meta info is too long
+	Width              int    // pin help message width, skip COLUMNS env & terminal detection
+	DefaultWidth       int    // help message width when it can't be decided from COLUMNS env or terminal, default to 80
+	AddHelpCommand     bool   // set true to register 'help' sub command for the root parser [help <cmd> ...]
+
+	Category        string // category of the sub command in parent's help, like 'Cluster Management'
+	HideCommand     bool   // hide the sub command from parent's help & completion
+	Deprecated      string // deprecation message of the sub command, it still works but hidden, warns when it's used
+	SortCommands    bool   // list sub commands in alphabetical order in help
+	CompactCommands bool   // list nested sub commands with indentation in help
+
+	Version     string        // set version to register version entry [-V/--version]
+	VersionFunc func() string // decide version when it's showed, like BuildVersion, used when Version is empty
 
 	WithColor   bool         // enable colorful help message if the terminal has support for color
-	EnsureColor bool         // use color code for sure, skip terminal env check
+	EnsureColor bool         // use color code for sure, skip terminal & env check
 	ColorSchema *ColorSchema // use given color schema to draw help info
+
+	Stdout io.Writer // writer for help message, completion script, etc. default to os.Stdout
+	Stderr io.Writer // writer for warnings & errors, default to os.Stderr
+
+	DeprecationHandler func(warning string) // handle deprecation warnings instead of writing to Stderr
+
+	Exit      func(code int)    // function to exit the program in ParseOrExit, default to os.Exit
+	ExitCodes map[ErrorCode]int // exit codes for typed errors in ParseOrExit, default to 2
+
+	ShowUsageOnError bool // errors returned by Parse show usage of the parser producing it, with a help hint
+
+	Examples []Example // usage examples of the command, shown in help & documents
+
+	HelpTemplate  string // text/template to render help message with HelpData, default to DefaultHelpTemplate
+	UsageTemplate string // text/template to render usage line with UsageData, default to DefaultUsageTemplate
 }
 
 // NewParser create the parser object with optional name & description & ParserConfig
 func NewParser(name string, description string, config *ParserConfig) *Parser {
+	parser := newParser(name, description, config)
+	if e := parser.loadTemplates(); e != nil {
+		panic(e.Error())
+	}
+	if parser.config.AddHelpCommand {
+		parser.addHelpCommand()
+	}
+	return parser
+}
+
+// newParser create the parser object, it's shared by the root parser & sub command parsers
+func newParser(name string, description string, config *ParserConfig) *Parser {
 	if config == nil {
 		config = &ParserConfig{}
 	}
@@ -76,6 +130,9 @@ func NewParser(name string, description string, config *ParserConfig) *Parser {
 
 		subParser:    []*Parser{},
 		subParserMap: make(map[string]*Parser),
+
+		helpTemplate:  defaultHelpTemplate,
+		usageTemplate: defaultUsageTemplate,
 	}
 	if !config.DisableHelp {
 		parser.showHelp = parser.Flag("h", "help",
@@ -85,6 +142,14 @@ func NewParser(name string, description string, config *ParserConfig) *Parser {
 		parser.showShellCompletion = parser.Flag("", "completion",
 			&Option{Help: "show command completion script"})
 	}
+	if config.Version != "" || config.VersionFunc != nil {
+		parser.showVersion = parser.Flag("V", "version",
+			&Option{Help: "show version info"})
+	}
+	if config.AddHelpJSON {
+		parser.showHelpJSON = parser.Flag("", "help-json",
+			&Option{Help: "show json description of the command", HideEntry: true})
+	}
 	return parser
 }
 
@@ -103,12 +168,33 @@ func (p *Parser) registerArgument(a *arg) error {
 	if e != nil {
 		return e
 	}
+	if a.ReplacedBy != "" { // replacement must be registered before, and a flag is only replaced by a flag
+		replacement := p.findArgument(a.ReplacedBy)
+		if replacement == nil {
+			return fmt.Errorf("replacement '%s' is not found", a.ReplacedBy)
+		}
+		if a.isFlag && !replacement.isFlag {
+			return fmt.Errorf("flag replaced by non-flag '%s'", a.ReplacedBy)
+		}
+		if !a.isFlag && replacement.isFlag {
+			return fmt.Errorf("non-flag replaced by flag '%s'", a.ReplacedBy)
+		}
+	}
+	// check conflicts before any change to the parser
+	if a.Positional {
+		id := a.getMetaName()
+		if match, exist := p.positionalPool[id]; exist && !match.Inheritable {
+			return ConflictError{Kind: "positional", Name: id, Description: match.Help}
+		}
+	}
+	for _, watcher := range a.getWatchers() {
+		if match, exist := p.entryMap[watcher]; exist && !match.Inheritable {
+			return ConflictError{Kind: "entry", Name: watcher, Description: match.Help}
+		}
+	}
 	if a.Positional {
 		id := a.getMetaName()
 		if match, exist := p.positionalPool[id]; exist {
-			if !match.Inheritable {
-				return fmt.Errorf("conflict positional for '%s', say: '%s'", id, match.Help)
-			}
 			// remove inheritable positional
 			pos := -1
 			for i, e := range p.positionArgs {
@@ -130,9 +216,6 @@ func (p *Parser) registerArgument(a *arg) error {
 	}
 	for _, watcher := range a.getWatchers() { // register optional arguments to 'entryMap'
 		if match, exist := p.entryMap[watcher]; exist {
-			if !match.Inheritable {
-				return fmt.Errorf("conflict entry for '%s', say: '%s'", watcher, match.Help)
-			}
 			// remove inheritable option
 			pos := -1
 			for i, e := range p.entries {
@@ -152,186 +235,240 @@ func (p *Parser) registerArgument(a *arg) error {
 
 func (p *Parser) registerParser(parser *Parser) error {
 	if match, exist := p.subParserMap[parser.name]; exist {
-		return fmt.Errorf("conflict sub command for '%s', desc: '%s'",
-			parser.name, match.description)
+		return ConflictError{Kind: "sub command", Name: parser.name, Description: match.description}
 	}
 	p.subParser = append(p.subParser, parser)
 	p.subParserMap[parser.name] = parser
 	return nil
 }
 
-// PrintHelp print help message to stdout
+// stdout returns the writer for normal output
+func (p *Parser) stdout() io.Writer {
+	if p.config.Stdout != nil {
+		return p.config.Stdout
+	}
+	return os.Stdout
+}
+
+// stderr returns the writer for warnings & errors
+func (p *Parser) stderr() io.Writer {
+	if p.config.Stderr != nil {
+		return p.config.Stderr
+	}
+	return os.Stderr
+}
+
+// PrintHelp print help message to ParserConfig.Stdout, default to os.Stdout
 func (p *Parser) PrintHelp() {
-	fmt.Println(p.FormatHelp())
+	fmt.Fprintln(p.stdout(), p.FormatHelp())
 }
 
 // FormatHelp only format help message for manual use, you can decide when to print help message
 func (p *Parser) FormatHelp() string {
-	if !p.config.WithColor {
-		return p.FormatHelpWithColor(NoColor)
+	return p.FormatHelpWithColor(p.colorSchema(p.stdout()))
+}
+
+// FormatHelpE format help message like FormatHelp, but returns the error if help or usage template fails to render
+func (p *Parser) FormatHelpE() (string, error) {
+	if p.config.Usage == "" {
+		if _, e := renderTemplate(p.usageTemplate, p.usageData()); e != nil {
+			return "", e
+		}
 	}
+	return renderTemplate(p.helpTemplate, p.helpData(p.colorSchema(p.stdout())))
+}
 
-	if p.config.EnsureColor || checkTerminalColorSupport() {
-		schema := DefaultColor
+// colorSchema decide the color schema for the output by ParserConfig.WithColor, EnsureColor & ColorSchema
+func (p *Parser) colorSchema(out io.Writer) *ColorSchema {
+	if !p.config.WithColor {
+		return NoColor
+	}
+	if p.config.EnsureColor || checkTerminalColorSupport(out) {
 		if p.config.ColorSchema != nil {
-			schema = p.config.ColorSchema
+			return p.config.ColorSchema
 		}
-		return p.FormatHelpWithColor(schema)
+		return DefaultColor
 	}
-
-	return p.FormatHelpWithColor(NoColor)
+	return NoColor
 }
 
-// FormatHelpWithColor allows you to generate a colorful help message with the given schema
+// FormatHelpWithColor allows you to generate a colorful help message with the given schema,
+// the layout is decided by ParserConfig.HelpTemplate, default to DefaultHelpTemplate
 func (p *Parser) FormatHelpWithColor(schema *ColorSchema) string {
-	terminalWidth := decideTerminalWidth()
-	result := wrapperColor(p.formatUsage(), schema.Usage)
-	if p.description != "" {
-		result += "\n\n" + wrapperColor(p.description, schema.Description)
+	return executeTemplate(p.helpTemplate, p.helpData(schema))
+}
+
+// Example is a usage example of the command
+type Example struct {
+	Command     string `json:"command"`               // command line, like 'tool deploy --env prod'
+	Description string `json:"description,omitempty"` // what the command does
+}
+
+// examples returns usage examples of the command, nested sub commands sharing config are not included
+func (p *Parser) examples() []Example {
+	if p.parent != nil && p.parent.config == p.config {
+		return nil
 	}
-	// calculate header length
-	headerLength := 10 // here set minimum header length, the code after will find the max length of headers
-	for _, parser := range p.subParser {
-		l := len(parser.name)
-		if l > headerLength {
-			headerLength = l
-		}
+	return p.config.Examples
+}
+
+// deprecated returns deprecation message of the sub command, nested sub commands sharing config are not included
+func (p *Parser) deprecated() string {
+	if p.parent == nil || p.parent.config == p.config {
+		return ""
 	}
-	for _, arg := range p.positionArgs {
-		l, _ := arg.formatHelpHeader(schema.Argument, schema.Meta)
-		if l > headerLength {
-			headerLength = l
-		}
+	return p.config.Deprecated
+}
+
+// hidden tells whether the sub command is hidden from help & completion, nested sub commands sharing config are not included
+func (p *Parser) hidden() bool {
+	if p.parent == nil || p.parent.config == p.config {
+		return false
 	}
-	for _, arg := range p.entries {
-		l, _ := arg.formatHelpHeader(schema.Argument, schema.Meta)
-		if l > headerLength {
-			headerLength = l
-		}
+	return p.config.HideCommand || p.config.Deprecated != ""
+}
+
+// warn handle deprecation warnings by ParserConfig.DeprecationHandler, or write to ParserConfig.Stderr
+func (p *Parser) warn(warning string) {
+	if p.config.DeprecationHandler != nil {
+		p.config.DeprecationHandler(warning)
+		return
 	}
-	headerLength += 4 // 2 space padding around header, before & after
-	helpBreak := false
-	if p.config.MaxHeaderLength > 0 {
-		headerLength = p.config.MaxHeaderLength
-		helpBreak = true
+	fmt.Fprintf(p.stderr(), "%s %s\n", wrapperColor("warning:", p.colorSchema(p.stderr()).Warning), warning)
+}
+
+// consume parse values given by user for the argument, deprecated argument warns & forwards values to its replacement
+func (p *Parser) consume(a *arg, values []string) error {
+	if e := a.parseValue(values); e != nil {
+		return e
 	}
-	// sub command
-	if len(p.subParser) > 0 {
-		section := "\n\n" + wrapperColor("commands:", schema.GroupTitle)
-		for _, parser := range p.subParser {
-			section += "\n" + formatHelpRow(wrapperColor(parser.name, schema.Command), parser.description,
-				len(parser.name), headerLength, terminalWidth, helpBreak)
+	if a.Deprecated == "" {
+		return nil
+	}
+	name := a.getMetaName()
+	if !a.Positional {
+		name = a.getWatchers()[0]
+	}
+	p.warn(fmt.Sprintf("%s is deprecated: %s", name, a.Deprecated))
+	if a.ReplacedBy != "" {
+		replacement := p.findArgument(a.ReplacedBy)
+		if replacement == nil {
+			return fmt.Errorf("replacement '%s' of %s is not found", a.ReplacedBy, name)
 		}
-		result += section
+		return replacement.parseValue(values)
 	}
-	withHint := p.config.WithHint
+	return nil
+}
 
-	// positional arguments
-	if len(p.positionArgs) > 0 {
-		section := ""
-		for _, arg := range p.positionArgs {
-			if arg.Group != "" || arg.HideEntry {
-				continue
-			}
-			help := arg.Help
-			if withHint && !arg.NoHint {
-				help = arg.formatHelpWithExtraInfo()
-			}
-			size, header := arg.formatHelpHeader(schema.Argument, schema.Meta)
-			section += "\n" + formatHelpRow(header, help, size, headerLength, terminalWidth, helpBreak)
-		}
-		if section != "" {
-			section = "\n\n" + wrapperColor("positionals:", schema.GroupTitle) + section
+// visibleCommands returns sub commands to show in help & completion
+func (p *Parser) visibleCommands() []*Parser {
+	var result []*Parser
+	for _, parser := range p.subParser {
+		if parser.hidden() {
+			continue
 		}
-		result += section
+		result = append(result, parser)
 	}
+	if p.config.SortCommands {
+		sort.SliceStable(result, func(i, j int) bool {
+			return result[i].name < result[j].name
+		})
+	}
+	return result
+}
 
-	// optional arguments
-	if len(p.entries) > 0 { // dealing optional arguments present
-		parsed := make(map[string]bool)
-		section := ""
-		for _, arg := range p.entries {
-			if arg.Group != "" {
-				continue
-			}
-			identifier := arg.getIdentifier()
-			if _, exist := parsed[identifier]; exist {
-				continue
-			}
-			parsed[identifier] = true
-			if arg.HideEntry {
-				continue
-			}
-			help := arg.Help
-			if withHint && !arg.NoHint {
-				help = arg.formatHelpWithExtraInfo()
+// commandEntry is a sub command to show in help, depth is the indent level for nested sub command
+type commandEntry struct {
+	parser *Parser
+	depth  int
+}
+
+// listCommands returns sub commands to show in help grouped by category,
+// commands without category come first, then categories in registration order.
+// nested sub commands follow their parents in compact mode
+func (p *Parser) listCommands() (categories []string, commands map[string][]commandEntry) {
+	commands = make(map[string][]commandEntry)
+	var nested func(parser *Parser, depth int) []commandEntry
+	nested = func(parser *Parser, depth int) []commandEntry {
+		result := []commandEntry{{parser, depth}}
+		if p.config.CompactCommands {
+			for _, sub := range parser.visibleCommands() {
+				result = append(result, nested(sub, depth+1)...)
 			}
-			size, header := arg.formatHelpHeader(schema.Argument, schema.Meta)
-			section += "\n" + formatHelpRow(header, help, size, headerLength, terminalWidth, helpBreak)
-		}
-		if section != "" {
-			section = "\n\n" + wrapperColor("options:", schema.GroupTitle) + section
 		}
-		result += section
+		return result
 	}
-
-	// argument groups
-	for _, group := range p.entryGroupOrder {
-		section := "\n\n" + wrapperColor(group+":", schema.GroupTitle)
-		content := ""
-		for _, arg := range p.entryGroup[group] {
-			if arg.HideEntry {
-				continue
-			}
-			help := arg.Help
-			if withHint && !arg.NoHint {
-				help = arg.formatHelpWithExtraInfo()
-			}
-			size, header := arg.formatHelpHeader(schema.Argument, schema.Meta)
-			content += "\n" + formatHelpRow(header, help, size, headerLength, terminalWidth, helpBreak)
+	for _, parser := range p.visibleCommands() {
+		category := parser.config.Category
+		if parser.config == p.config { // sub command sharing config with parent has no category
+			category = ""
 		}
-		if content != "" {
-			result += section + content
+		if _, exist := commands[category]; !exist {
+			categories = append(categories, category)
 		}
+		commands[category] = append(commands[category], nested(parser, 0)...)
 	}
-	if p.config.EpiLog != "" {
-		result += "\n\n" + wrapperColor(p.config.EpiLog, schema.Epilog)
-	}
+	sort.SliceStable(categories, func(i, j int) bool {
+		return categories[i] == "" && categories[j] != ""
+	})
+	return
+}
 
+// visiblePositionals returns positional arguments without group to show in help
+func (p *Parser) visiblePositionals() []*arg {
+	var result []*arg
+	for _, arg := range p.positionArgs {
+		if arg.Group != "" || arg.hidden() {
+			continue
+		}
+		result = append(result, arg)
+	}
 	return result
 }
 
-func (p *Parser) formatUsage() string {
-	usage := "usage: "
-	if p.config.Usage != "" {
-		return usage + p.config.Usage
-	}
-	for _, parent := range p.parentList { // sub command usage
-		usage += parent + " "
-	}
-	usage += p.name + " "
-	if len(p.subParser) > 0 {
-		usage += "<cmd> "
-	}
+// visibleOptions returns optional arguments without group to show in help, each argument only once
+func (p *Parser) visibleOptions() []*arg {
+	var result []*arg
 	parsed := make(map[string]bool)
 	for _, arg := range p.entries {
+		if arg.Group != "" {
+			continue
+		}
 		identifier := arg.getIdentifier()
 		if _, exist := parsed[identifier]; exist {
 			continue
 		}
 		parsed[identifier] = true
-		argUsage := arg.formatUsage()
-		if argUsage != "" {
-			usage += argUsage
+		if arg.hidden() {
+			continue
 		}
+		result = append(result, arg)
 	}
-	for _, arg := range p.positionArgs {
-		argUsage := arg.formatUsage()
-		if argUsage != "" {
-			usage += argUsage
+	return result
+}
+
+// visibleGroupEntries returns arguments of the group to show in help
+func (p *Parser) visibleGroupEntries(group string) []*arg {
+	var result []*arg
+	for _, arg := range p.entryGroup[group] {
+		if arg.hidden() {
+			continue
 		}
+		result = append(result, arg)
 	}
-	return usage
+	return result
+}
+
+// commandPath returns names from the root parser to current parser
+func (p *Parser) commandPath() []string {
+	return append(append([]string{}, p.parentList...), p.name)
+}
+
+func (p *Parser) formatUsage() string {
+	if p.config.Usage != "" {
+		return "usage: " + p.config.Usage
+	}
+	return executeTemplate(p.usageTemplate, p.usageData())
 }
 
 // formatBashCompletionScript will generate bash shell script
@@ -340,16 +477,19 @@ func (p *Parser) formatBashCompletionScript() string {
 	var topLevel []string
 	subLevelMap := make(map[string]string)
 	for entry, arg := range p.entryMap {
-		if arg.HideEntry {
+		if arg.hidden() || arg.isHiddenAlias(entry) {
 			continue
 		}
 		topLevel = append(topLevel, entry)
 	}
 	for entry, subParser := range p.subParserMap {
+		if subParser.hidden() {
+			continue
+		}
 		topLevel = append(topLevel, entry)
 		var subOptions []string
 		for subOption, arg := range subParser.entryMap {
-			if arg.HideEntry {
+			if arg.hidden() || arg.isHiddenAlias(subOption) {
 				continue
 			}
 			subOptions = append(subOptions, subOption)
@@ -361,6 +501,34 @@ func (p *Parser) formatBashCompletionScript() string {
 		subCompletions = append(subCompletions,
 			fmt.Sprintf("    %s) COMPREPLY=( $(compgen -W \"%s\" -- $cur ) ) ;;", entry, candidates))
 	}
+	// complete files or directories after path arguments
+	pathCompletions := make(map[string][]string)
+	for _, parser := range append([]*Parser{p}, p.subParser...) {
+		for entry, arg := range parser.entryMap {
+			if !arg.isPath || arg.hidden() || arg.isHiddenAlias(entry) || len(arg.Choices) > 0 {
+				continue
+			}
+			action := "-f"
+			if arg.PathCheck&PathIsDir != 0 {
+				action = "-d"
+			}
+			pathCompletions[action] = append(pathCompletions[action], entry)
+		}
+	}
+	pathCompletionsScript := ""
+	for _, action := range []string{"-f", "-d"} {
+		if entries := pathCompletions[action]; len(entries) > 0 {
+			sort.Strings(entries)
+			pathCompletionsScript += fmt.Sprintf("\n      %s) COMPREPLY=($(compgen %s -- $cur)) ; return ;;",
+				strings.Join(entries, "|"), action)
+		}
+	}
+	if pathCompletionsScript != "" {
+		pathCompletionsScript = fmt.Sprintf(`
+    case "${COMP_WORDS[COMP_CWORD-1]}" in%s
+    esac
+`, pathCompletionsScript)
+	}
 	subCompletionsScript := ""
 	if len(subCompletions) > 0 {
 		subCompletionsScript = fmt.Sprintf(`
@@ -372,7 +540,7 @@ func (p *Parser) formatBashCompletionScript() string {
 	return fmt.Sprintf(`
   %s() {
     local i=1 cur="${COMP_WORDS[COMP_CWORD]}" cmd
-
+%s
     while [[ "$i" -lt "$COMP_CWORD" ]]
     do
       local s="${COMP_WORDS[i]}"
@@ -397,7 +565,37 @@ func (p *Parser) formatBashCompletionScript() string {
   }
 
   complete -o bashdefault -o default -F %s %s
-`, completionName, shortPrefix, strings.Join(topLevel, " "), subCompletionsScript, completionName, p.name)
+`, completionName, pathCompletionsScript, shortPrefix, strings.Join(topLevel, " "), subCompletionsScript, completionName, p.name)
+}
+
+// zshUnsafe are characters breaking the quoted completion script
+var zshUnsafe = strings.NewReplacer("\"", "", "'", "", "`", "", "$", "", "\\", "")
+
+// zshArgumentSpec returns the spec of the entry for zsh _arguments, choices are completed with their help
+func zshArgumentSpec(entry string, a *arg) string {
+	if a.isPath && len(a.Choices) == 0 { // complete files, or directories only
+		if a.PathCheck&PathIsDir != 0 {
+			return fmt.Sprintf("\"%s:%s:_files -/\"", entry, a.getMetaName())
+		}
+		return fmt.Sprintf("\"%s:%s:_files\"", entry, a.getMetaName())
+	}
+	if len(a.Choices) == 0 {
+		return fmt.Sprintf("\"%s\"", entry)
+	}
+	var values []string
+	for _, c := range a.Choices {
+		value := strings.NewReplacer(":", "\\:", " ", "\\ ").Replace(zshUnsafe.Replace(formatChoice(c)))
+		if a.hasChoiceHelp() {
+			if choice, ok := c.(Choice); ok && choice.Help != "" {
+				value += fmt.Sprintf("\\:'%s'", zshUnsafe.Replace(choice.Help))
+			}
+		}
+		values = append(values, value)
+	}
+	if a.hasChoiceHelp() {
+		return fmt.Sprintf("\"%s:%s:((%s))\"", entry, a.getMetaName(), strings.Join(values, " "))
+	}
+	return fmt.Sprintf("\"%s:%s:(%s)\"", entry, a.getMetaName(), strings.Join(values, " "))
 }
 
 // formatZshCompletionScript will generate zsh shell script
@@ -406,23 +604,25 @@ func (p *Parser) formatZshCompletionScript() string {
 	var positional []string
 	var positionalFirstSection []string
 	for entry, arg := range p.entryMap {
-		if arg.HideEntry {
+		if arg.hidden() || arg.isHiddenAlias(entry) {
 			continue
 		}
 		positional = append(positional, entry)
-		positionalFirstSection = append(positionalFirstSection,
-			fmt.Sprintf("\"%s\"", entry))
+		positionalFirstSection = append(positionalFirstSection, zshArgumentSpec(entry, arg))
 	}
 
 	subLevelPosition := ""
 	subLevelMap := make(map[string]string)
 	for entry, subParser := range p.subParserMap {
+		if subParser.hidden() {
+			continue
+		}
 		var subOptions []string
 		for subOption, arg := range subParser.entryMap {
-			if arg.HideEntry {
+			if arg.hidden() || arg.isHiddenAlias(subOption) {
 				continue
 			}
-			subOptions = append(subOptions, fmt.Sprintf("\"%s\"", subOption))
+			subOptions = append(subOptions, zshArgumentSpec(subOption, arg))
 		}
 		subLevelPosition += entry + " "
 		subLevelMap[entry] = strings.Join(subOptions, " ")
@@ -484,16 +684,33 @@ func findExtraPositionalArgs(args []string) (idx int, remains []string) {
 // Parse will parse given args to bind to any registered arguments
 //
 // args: set nil to use os.Args[1:] by default
+//
+// failures during parsing are wrapped in ParseError with the parser producing it,
+// the wrapped errors are typed errors like UnknownArgumentError, RequiredError, etc. use errors.As to check them
 func (p *Parser) Parse(args []string) error {
 	if args == nil {
 		args = os.Args[1:]
 	}
+	e := p.parse(args)
+	switch e.(type) {
+	case nil, BreakAfterHelp, BreakAfterShellScript, BreakAfterVersion, ParseError: // error from sub command is wrapped already
+		return e
+	}
+	return ParseError{
+		Parser: p,
+		Usage:  strings.TrimRight(p.formatUsage(), " "),
+		Err:    bindPath(e, strings.Join(p.commandPath(), " ")),
+	}
+}
+
+func (p *Parser) parse(args []string) error {
 	extraIdx, remains := findExtraPositionalArgs(args)
 	hasExtra := len(remains) > 0
 	if hasExtra || extraIdx > 0 {
 		args = args[:extraIdx]
 	}
 
+	var positionalTokens []string
 	if len(args) == 0 && !hasExtra {
 		if p.config.DefaultAction != nil {
 			p.config.DefaultAction()
@@ -504,17 +721,30 @@ func (p *Parser) Parse(args []string) error {
 	} else {
 		if len(p.subParser) > 0 {
 			if subParser, match := p.subParserMap[args[0]]; match {
-				return subParser.Parse(args[1:])
+				if subParser == p.helpCommand {
+					return p.runHelpCommand(args[1:])
+				}
+				if deprecated := subParser.deprecated(); deprecated != "" {
+					subParser.warn(fmt.Sprintf("command '%s' is deprecated: %s",
+						strings.Join(subParser.commandPath(), " "), deprecated))
+				}
+				if e := subParser.Parse(args[1:]); e != nil {
+					return e
+				}
+				if e := p.applyDefaults(); e != nil {
+					return e
+				}
+				return p.validate() // parsers in the command chain validate from inner to outer
 			}
 		}
-		lastPositionArgIndex := 0
-		registeredPositionsLength := len(p.positionArgs)
 		for len(args) > 0 {
 			// iterate user input args
 			sign := args[0]
 			if arg, ok := p.entryMap[sign]; ok {
 				if arg.isFlag {
-					_ = arg.parseValue(nil)
+					if e := p.consume(arg, nil); e != nil {
+						return e
+					}
 					args = args[1:]
 				} else {
 					// find user inputs before next registered optional argument
@@ -528,21 +758,20 @@ func (p *Parser) Parse(args []string) error {
 					}
 					// argument takes at least one input as argument, but there is 0
 					if len(tillNext) == 0 {
-						return fmt.Errorf("argument %s expect argument",
-							strings.Join(arg.getWatchers(), "/"))
+						return MissingValueError{Argument: strings.Join(arg.getVisibleWatchers(), "/")}
 					}
 					// if argument takes more than one arguments,
 					// it will take all user input before next registered argument,
 					// and proceed 'args' parsing to next registered argument
 					if arg.multi {
-						e := arg.parseValue(tillNext)
+						e := p.consume(arg, tillNext)
 						if e != nil {
 							return e
 						}
 						args = args[len(tillNext)+1:]
 					} else {
 						// then the argument takes only one argument
-						e := arg.parseValue(tillNext[0:1])
+						e := p.consume(arg, tillNext[0:1])
 						if e != nil {
 							return e
 						}
@@ -551,59 +780,31 @@ func (p *Parser) Parse(args []string) error {
 					}
 				}
 			} else {
-				// while there is unparsed positional argument
-				if registeredPositionsLength > lastPositionArgIndex {
-					arg := p.positionArgs[lastPositionArgIndex]
-					lastPositionArgIndex += 1
-					// find user inputs before next registered optional argument
-					var tillNext []string
-					for _, a := range args {
-						if _, isEntry := p.entryMap[a]; !isEntry {
-							tillNext = append(tillNext, a)
-						} else {
-							break
-						}
-					}
-					if arg.multi {
-						// if any multi-type positional required,
-						// extra arguments with be regard as part of it
-						e := arg.parseValue(append(tillNext, remains...))
-						if e != nil {
-							return e
-						}
-						remains = []string{}
-						args = args[len(tillNext):]
-					} else {
-						e := arg.parseValue(tillNext[0:1])
-						if e != nil {
-							return e
-						}
-						args = args[1:]
-					}
-				} else {
-					if strings.HasPrefix(sign, shortPrefix) {
-						var candidates []string
-						for k := range p.entryMap {
-							candidates = append(candidates, k)
-						}
-						var tips []string
-						for _, m := range decideMatch(sign, candidates) {
-							helpInfo := p.entryMap[m].Help
-							if helpInfo != "" {
-								helpInfo = fmt.Sprintf(" (%s)", helpInfo)
-							}
-							tips = append(tips, fmt.Sprintf("%s%s", m, helpInfo))
-						}
-						match := strings.Join(tips, "\nor ")
-						if match != "" {
-							return fmt.Errorf("unrecognized arguments: %s\ndo you mean?: %s", sign, match)
-						}
-					}
-					return fmt.Errorf("unrecognized arguments: %s", sign)
-				}
+				// positional arguments are assigned after all inputs are scanned
+				positionalTokens = append(positionalTokens, sign)
+				args = args[1:]
 			}
 		}
 	}
+	if e := p.assignPositionals(append(positionalTokens, remains...)); e != nil {
+		return e
+	}
+	if p.showHelpJSON != nil && *p.showHelpJSON {
+		content, e := p.DescribeJSON()
+		if e != nil {
+			return e
+		}
+		fmt.Fprintln(p.stdout(), string(content))
+		return BreakAfterHelpError
+	}
+	if p.showVersion != nil && *p.showVersion {
+		version := p.config.Version
+		if version == "" {
+			version = p.config.VersionFunc()
+		}
+		fmt.Fprintln(p.stdout(), version)
+		return BreakAfterVersionError
+	}
 	if p.showHelp != nil && *p.showHelp {
 		p.PrintHelp()
 		if !p.config.ContinueOnHelp {
@@ -611,46 +812,129 @@ func (p *Parser) Parse(args []string) error {
 		}
 	}
 	if p.showShellCompletion != nil && *p.showShellCompletion {
-		fmt.Println(p.FormatCompletionScript())
+		fmt.Fprintln(p.stdout(), p.FormatCompletionScript())
 		return BreakAfterShellScriptError
 	}
 
-	// apply extra arguments for only positional
-	if len(remains) > 0 {
-		for _, arg := range p.positionArgs {
-			if arg.assigned {
-				continue
-			}
-			if arg.multi {
-				e := arg.parseValue(remains)
-				if e != nil {
-					return e
-				}
-			} else {
-				e := arg.parseValue(remains[0:1])
-				if e != nil {
-					return e
-				}
-				remains = remains[1:]
+	if e := p.applyDefaults(); e != nil {
+		return e
+	}
+	entries := append(p.entries, p.positionArgs...)
+	for _, arg := range entries { // check Required
+		if arg.Required && !arg.assigned {
+			return RequiredError{Argument: arg.getMetaName()}
+		}
+		if arg.assigned && (arg.MinLen > 0 || arg.MaxLen > 0) {
+			if e := arg.checkCount(); e != nil {
+				return e
 			}
 		}
 	}
 
-	entries := append(p.entries, p.positionArgs...)
-	for _, arg := range entries { // check Required & set Default value
+	if e := p.validate(); e != nil {
+		return e
+	}
+	for _, arg := range entries { // open files only after parsing succeeds, so nothing is truncated on failure
+		if e := arg.bindFile(); e != nil {
+			return e
+		}
+	}
+
+	p.Invoked = true
+	if p.InvokeAction != nil {
+		p.InvokeAction(p.Invoked)
+	}
+	return nil
+}
+
+// applyDefaults bind Default value to arguments not given by user
+func (p *Parser) applyDefaults() error {
+	for _, arg := range append(p.entries, p.positionArgs...) {
 		if !arg.assigned && arg.Default != "" {
 			if e := arg.parseValue(nil); e != nil {
 				return e
 			}
+			arg.source = SourceDefault
 		}
-		if arg.Required && !arg.assigned {
-			return fmt.Errorf("%s is required", arg.getMetaName())
+	}
+	return nil
+}
+
+// assignPositionals assign user inputs to positional arguments in order like python argparse,
+// each positional takes as many inputs as it can, leaving enough for the required positionals after it,
+// so that 'SRC [SRC ...] DST' works. inputs left are unrecognized
+func (p *Parser) assignPositionals(tokens []string) error {
+	reserved := 0 // inputs required by positionals after current one
+	for _, a := range p.positionArgs {
+		if a.Required {
+			reserved += 1
+		}
+	}
+	for _, a := range p.positionArgs {
+		least := 0
+		if a.Required {
+			least = 1
+			reserved -= 1
+		}
+		if len(tokens) == 0 {
+			break
+		}
+		take := len(tokens) - reserved
+		if !a.multi && take > 1 {
+			take = 1
+		}
+		if take < least { // too few inputs, the required positionals after it will be missing
+			take = least
+		}
+		if take <= 0 { // leave inputs for the required positionals after it
+			continue
 		}
+		if e := p.consume(a, tokens[:take]); e != nil {
+			return e
+		}
+		tokens = tokens[take:]
+	}
+	if len(tokens) > 0 {
+		return p.unknownArgument(tokens[0])
 	}
+	return nil
+}
 
-	p.Invoked = true
-	if p.InvokeAction != nil {
-		p.InvokeAction(p.Invoked)
+// unknownArgument returns UnknownArgumentError for the input, with similar entries suggested
+func (p *Parser) unknownArgument(sign string) error {
+	e := UnknownArgumentError{Token: sign}
+	if !strings.HasPrefix(sign, shortPrefix) {
+		return e
+	}
+	var candidates []string
+	for k := range p.entryMap {
+		candidates = append(candidates, k)
+	}
+	for _, m := range decideMatch(sign, candidates) {
+		helpInfo := p.entryMap[m].Help
+		if helpInfo != "" {
+			helpInfo = fmt.Sprintf(" (%s)", helpInfo)
+		}
+		e.Suggestions = append(e.Suggestions, m)
+		e.tips = append(e.tips, fmt.Sprintf("%s%s", m, helpInfo))
+	}
+	return e
+}
+
+// AddValidator add a validator running after the parser & its sub command is parsed, defaults & required checked,
+// which can check relations among arguments, like '--start must be before --end'.
+// File arguments are opened after validators pass, so they are still nil in validators.
+// errors returned are wrapped in ValidationError with the command path
+func (p *Parser) AddValidator(validator func(parser *Parser) error) {
+	p.validators = append(p.validators, validator)
+}
+
+// validate run validators in order, stop at the first error
+func (p *Parser) validate() error {
+	for _, validator := range p.validators {
+		if e := validator(p); e != nil {
+			return ValidationError{Err: e}
+		}
 	}
 	return nil
 }
@@ -659,20 +943,49 @@ func (p *Parser) Parse(args []string) error {
 //
 // Return a new pointer to sub command parser
 func (p *Parser) AddCommand(name string, description string, config *ParserConfig) *Parser {
+	parser, e := p.addCommand(name, description, config)
+	if e != nil {
+		panic(e.Error())
+	}
+	return parser
+}
+
+// addCommand create the sub command parser, the parser is returned even if it failed to register
+func (p *Parser) addCommand(name string, description string, config *ParserConfig) (*Parser, error) {
 	if config == nil {
 		config = p.config
 	}
+	config.AddShellCompletion = false // disable sub command completion
+	// inherit writers & width from parent
+	if config.Stdout == nil {
+		config.Stdout = p.config.Stdout
+	}
+	if config.Stderr == nil {
+		config.Stderr = p.config.Stderr
+	}
+	if config.DeprecationHandler == nil {
+		config.DeprecationHandler = p.config.DeprecationHandler
+	}
+	if config.Width == 0 {
+		config.Width = p.config.Width
+	}
+	if config.DefaultWidth == 0 {
+		config.DefaultWidth = p.config.DefaultWidth
+	}
+	parser := newParser(name, description, config)
+	parser.parentList = append(p.parentList, p.name)
+	parser.parent = p
+	if e := parser.loadTemplates(); e != nil {
+		return parser, e
+	}
 	if name == "" {
-		panic("sub command name is empty")
+		return parser, fmt.Errorf("sub command name is empty")
 	}
 	if strings.Contains(name, " ") {
-		panic("sub command name has space")
+		return parser, fmt.Errorf("sub command name has space")
 	}
-	config.AddShellCompletion = false // disable sub command completion
-	parser := NewParser(name, description, config)
-	parser.parentList = append(p.parentList, p.name)
 	if e := p.registerParser(parser); e != nil {
-		panic(e.Error())
+		return parser, e
 	}
 	for _, a := range p.positionArgs {
 		if a.Inheritable {
@@ -690,28 +1003,51 @@ func (p *Parser) AddCommand(name string, description string, config *ParserConfi
 			exist[key] = 1
 		}
 	}
-	return parser
+	return parser, nil
 }
 
-// Flag create flag argument, Return a "*bool" point to the parse result
-//
-// python version is like add_argument("-s", "--full", action="store_true")
-//
-// Flag Argument can only be used as an OptionalArguments
-func (p *Parser) Flag(short, full string, opts *Option) *bool {
-	var result bool
+// newArg create argument binding to target, target decides argument type
+func newArg(short, full string, target interface{}, opts *Option) *arg {
 	if opts == nil {
 		opts = &Option{}
 	}
-	opts.isFlag = true
-	if e := p.registerArgument(&arg{
+	a := &arg{
 		short:  short,
 		full:   full,
-		target: &result,
+		target: target,
 		Option: *opts,
-	}); e != nil {
+	}
+	switch target.(type) {
+	case *bool:
+		a.isFlag = true
+	case *[]string, *[]int, *[]float64:
+		a.multi = true
+	}
+	return a
+}
+
+// newPathArg create the argument for File, Path & Paths
+func newPathArg(short, full string, target interface{}, opts *Option) *arg {
+	a := newArg(short, full, target, opts)
+	a.isPath = true
+	return a
+}
+
+// mustRegister register the argument, panic if failed
+func (p *Parser) mustRegister(a *arg) {
+	if e := p.registerArgument(a); e != nil {
 		panic(e.Error())
 	}
+}
+
+// Flag create flag argument, Return a "*bool" point to the parse result
+//
+// python version is like add_argument("-s", "--full", action="store_true")
+//
+// Flag Argument can only be used as an OptionalArguments
+func (p *Parser) Flag(short, full string, opts *Option) *bool {
+	var result bool
+	p.mustRegister(newArg(short, full, &result, opts))
 	return &result
 }
 
@@ -722,17 +1058,7 @@ func (p *Parser) Flag(short, full string, opts *Option) *bool {
 // set Option.Positional = true to use as Positional Argument, then it's like add_argument("s", "full") in python
 func (p *Parser) String(short, full string, opts *Option) *string {
 	var result string
-	if opts == nil {
-		opts = &Option{}
-	}
-	if e := p.registerArgument(&arg{
-		short:  short,
-		full:   full,
-		target: &result,
-		Option: *opts,
-	}); e != nil {
-		panic(e.Error())
-	}
+	p.mustRegister(newArg(short, full, &result, opts))
 	return &result
 }
 
@@ -743,18 +1069,7 @@ func (p *Parser) String(short, full string, opts *Option) *string {
 // python version is like add_argument("-s", "--full", nargs="*") or add_argument("s", "full", nargs="*")
 func (p *Parser) Strings(short, full string, opts *Option) *[]string {
 	var result []string
-	if opts == nil {
-		opts = &Option{}
-	}
-	opts.multi = true
-	if e := p.registerArgument(&arg{
-		short:  short,
-		full:   full,
-		target: &result,
-		Option: *opts,
-	}); e != nil {
-		panic(e.Error())
-	}
+	p.mustRegister(newArg(short, full, &result, opts))
 	return &result
 }
 
@@ -765,17 +1080,7 @@ func (p *Parser) Strings(short, full string, opts *Option) *[]string {
 // python version is like add_argument("s", "full", type=int) or add_argument("-s", "--full", type=int)
 func (p *Parser) Int(short, full string, opts *Option) *int {
 	var result int
-	if opts == nil {
-		opts = &Option{}
-	}
-	if e := p.registerArgument(&arg{
-		short:  short,
-		full:   full,
-		target: &result,
-		Option: *opts,
-	}); e != nil {
-		panic(e.Error())
-	}
+	p.mustRegister(newArg(short, full, &result, opts))
 	return &result
 }
 
@@ -786,18 +1091,7 @@ func (p *Parser) Int(short, full string, opts *Option) *int {
 // python version is like add_argument("s", "full", type=int, nargs="*") or add_argument("-s", "--full", type=int, nargs="*")
 func (p *Parser) Ints(short, full string, opts *Option) *[]int {
 	var result []int
-	if opts == nil {
-		opts = &Option{}
-	}
-	opts.multi = true
-	if e := p.registerArgument(&arg{
-		short:  short,
-		full:   full,
-		target: &result,
-		Option: *opts,
-	}); e != nil {
-		panic(e.Error())
-	}
+	p.mustRegister(newArg(short, full, &result, opts))
 	return &result
 }
 
@@ -808,17 +1102,7 @@ func (p *Parser) Ints(short, full string, opts *Option) *[]int {
 // python version is like add_argument("-s", "--full", type=double) or add_argument("s", "full", type=double)
 func (p *Parser) Float(short, full string, opts *Option) *float64 {
 	var result float64
-	if opts == nil {
-		opts = &Option{}
-	}
-	if e := p.registerArgument(&arg{
-		short:  short,
-		full:   full,
-		target: &result,
-		Option: *opts,
-	}); e != nil {
-		panic(e.Error())
-	}
+	p.mustRegister(newArg(short, full, &result, opts))
 	return &result
 }
 
@@ -829,17 +1113,32 @@ func (p *Parser) Float(short, full string, opts *Option) *float64 {
 // python version is like add_argument("-s", "--full", type=double, nargs="*") or add_argument("s", "full", type=double, nargs="*")
 func (p *Parser) Floats(short, full string, opts *Option) *[]float64 {
 	var result []float64
-	if opts == nil {
-		opts = &Option{}
-	}
-	opts.multi = true
-	if e := p.registerArgument(&arg{
-		short:  short,
-		full:   full,
-		target: &result,
-		Option: *opts,
-	}); e != nil {
-		panic(e.Error())
-	}
+	p.mustRegister(newArg(short, full, &result, opts))
+	return &result
+}
+
+// File create file argument, return a **os.File point to the opened file
+//
+// the file is opened after parsing succeeds, to read, or to write if PathWritable is set in Option.PathCheck,
+// '-' means stdin/stdout with PathAllowStdio. it's the caller's duty to close the file
+func (p *Parser) File(short, full string, opts *Option) **os.File {
+	var result *os.File
+	p.mustRegister(newPathArg(short, full, &result, opts))
+	return &result
+}
+
+// Path create path argument, return a *string point to the cleaned path, checked by Option.PathCheck
+func (p *Parser) Path(short, full string, opts *Option) *string {
+	var result string
+	p.mustRegister(newPathArg(short, full, &result, opts))
+	return &result
+}
+
+// Paths create path list argument, return a *[]string point to the cleaned paths
+//
+// mostly like *Parser.Path(), glob patterns can be expanded to multiple paths with PathExpandGlob
+func (p *Parser) Paths(short, full string, opts *Option) *[]string {
+	var result []string
+	p.mustRegister(newPathArg(short, full, &result, opts))
 	return &result
 }
@@ -1,6 +1,7 @@
 package argparse
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -248,6 +249,39 @@ func TestParser_Choices(t *testing.T) {
 
 }
 
+func TestChoiceHelp(t *testing.T) {
+	parser := NewParser("tool", "", &ParserConfig{Width: 80})
+	mode := parser.String("", "mode", &Option{Help: "run mode", IgnoreCase: true, Choices: []interface{}{
+		Choice{Value: "fast", Help: "skip all checks"}, Choice{Value: "safe", Help: "run all checks"}, "debug"}})
+	if e := parser.Parse([]string{"--mode", "FAST"}); e != nil {
+		t.Error(e.Error())
+		return
+	}
+	if *mode != "fast" {
+		t.Error("failed to bind choice case-insensitively")
+		return
+	}
+	if !strings.HasSuffix(parser.FormatHelp(), `
+  --mode MODE  run mode
+                 fast   skip all checks
+                 safe   run all checks
+                 debug`) {
+		t.Error("failed to show choices with help")
+		return
+	}
+	parser = NewParser("tool", "", nil)
+	parser.String("", "mode", &Option{Choices: []interface{}{Choice{Value: "fast", Help: "skip all checks"}}})
+	if e := parser.Parse([]string{"--mode", "Fast"}); e == nil || e.Error() != "args must be one|some of [fast]" {
+		t.Error("choice should be case-sensitive by default")
+		return
+	}
+	script := parser.formatZshCompletionScript()
+	if !strings.Contains(script, `"--mode:MODE:((fast\:'skip all checks'))"`) {
+		t.Error("failed to complete choices with help")
+		return
+	}
+}
+
 func TestParser_Validate(t *testing.T) {
 	parser := NewParser("", "", nil)
 	a := parser.String("", "a", &Option{Validate: func(arg string) error {
@@ -928,3 +962,464 @@ func TestParseWithExtra(t *testing.T) {
 		t.Error("failed to parse extra error")
 	}
 }
+
+func TestOutputWriter(t *testing.T) {
+	var out strings.Builder
+	p := NewParser("", "", &ParserConfig{Stdout: &out, AddShellCompletion: true})
+	sub := p.AddCommand("sub", "", &ParserConfig{})
+	if e := p.Parse([]string{"-h"}); e != BreakAfterHelpError {
+		t.Error("failed to break after help")
+		return
+	}
+	if !strings.HasPrefix(out.String(), "usage: ") {
+		t.Error("failed to print help to writer")
+		return
+	}
+	out.Reset()
+	p = NewParser("", "", &ParserConfig{Stdout: &out, AddShellCompletion: true})
+	sub = p.AddCommand("sub", "", &ParserConfig{})
+	if e := p.Parse([]string{"--completion"}); e != BreakAfterShellScriptError {
+		t.Error("failed to break after completion")
+		return
+	}
+	if !strings.Contains(out.String(), "###-begin-completion-###") {
+		t.Error("failed to print completion script to writer")
+		return
+	}
+	out.Reset()
+	if e := p.Parse([]string{"sub", "--help"}); e != BreakAfterHelpError {
+		t.Error("failed to break after sub command help")
+		return
+	}
+	if !strings.HasPrefix(out.String(), "usage: ") || sub.config.Stdout != &out {
+		t.Error("sub command should inherit writer")
+		return
+	}
+}
+
+func TestVersion(t *testing.T) {
+	var out strings.Builder
+	p := NewParser("", "", &ParserConfig{Version: "v1.0.0", Stdout: &out})
+	if e := p.Parse([]string{"-V"}); e != BreakAfterVersionError || out.String() != "v1.0.0\n" {
+		t.Error("failed to show version")
+		return
+	}
+	out.Reset()
+	p = NewParser("", "", &ParserConfig{VersionFunc: BuildVersion, Stdout: &out})
+	if e := p.Parse([]string{"--version"}); e != BreakAfterVersionError || out.String() == "\n" {
+		t.Error("failed to show build version")
+		return
+	}
+	code := -1
+	p = NewParser("", "", &ParserConfig{Version: "v1.0.0", Stdout: &out, Exit: func(c int) { code = c }})
+	p.ParseOrExit([]string{"-V"})
+	if code != 0 {
+		t.Error("failed to exit after version")
+		return
+	}
+	if NewParser("", "", nil).entryMap["--version"] != nil {
+		t.Error("version entry should not be registered")
+		return
+	}
+}
+
+func TestCommandCategory(t *testing.T) {
+	p := NewParser("tool", "", &ParserConfig{SortCommands: true, CompactCommands: true})
+	p.AddCommand("version", "show version", nil)
+	deploy := p.AddCommand("deploy", "deploy the app", &ParserConfig{Category: "Cluster Management"})
+	deploy.AddCommand("scale", "scale the app", nil)
+	p.AddCommand("logs", "show logs", &ParserConfig{Category: "Troubleshooting"})
+	p.AddCommand("apply", "apply config", &ParserConfig{Category: "Cluster Management"})
+	debug := p.AddCommand("debug", "secret", &ParserConfig{HideCommand: true})
+	debug.AddCommand("trace", "trace it", nil)
+
+	help := p.FormatHelp()
+	expect := `commands:
+  version     show version
+
+Cluster Management:
+  apply       apply config
+  deploy      deploy the app
+    scale     scale the app
+
+Troubleshooting:
+  logs        show logs`
+	if !strings.Contains(help, expect) {
+		t.Error("failed to list commands by category")
+		return
+	}
+	if strings.Contains(help, "debug") || strings.Contains(p.FormatCompletionScript(), "debug") {
+		t.Error("hidden command should not show")
+		return
+	}
+	if !strings.Contains(deploy.FormatHelp(), "commands:\n  scale") {
+		t.Error("sub command should list its own commands")
+		return
+	}
+	if !strings.Contains(debug.FormatHelp(), "commands:\n  trace") || !strings.Contains(debug.FormatCompletionScript(), "trace") {
+		t.Error("nested command sharing config should not be hidden")
+		return
+	}
+	if d := debug.Describe(); !d.Hidden || len(d.Commands) != 1 || d.Commands[0].Hidden {
+		t.Error("failed to describe hidden command")
+		return
+	}
+	if e := p.Parse([]string{"debug"}); e != BreakAfterHelpError {
+		t.Error("hidden command should still work")
+		return
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	var errOut strings.Builder
+	p := NewParser("tool", "", &ParserConfig{Stderr: &errOut})
+	timeToLive := p.Int("", "time-to-live", nil)
+	ttl := p.Int("", "ttl", &Option{Deprecated: "use --time-to-live instead", ReplacedBy: "time-to-live"})
+	old := p.AddCommand("old", "", &ParserConfig{Deprecated: "use new instead"})
+	old.Flag("f", "", nil)
+	p.AddCommand("new", "", nil)
+
+	help := p.FormatHelp()
+	if strings.Contains(help, "--ttl") || strings.Contains(help, "old") || strings.Contains(p.FormatCompletionScript(), "old") {
+		t.Error("deprecated entries should be hidden")
+		return
+	}
+	if e := p.Parse([]string{"--ttl", "10"}); e != nil {
+		t.Error(e)
+		return
+	}
+	if *ttl != 10 || *timeToLive != 10 {
+		t.Error("failed to forward value to replacement")
+		return
+	}
+	if errOut.String() != "warning: --ttl is deprecated: use --time-to-live instead\n" {
+		t.Error("failed to warn deprecated argument")
+		return
+	}
+
+	var warnings []string
+	p = NewParser("tool", "", &ParserConfig{DeprecationHandler: func(warning string) {
+		warnings = append(warnings, warning)
+	}})
+	old = p.AddCommand("old", "", &ParserConfig{Deprecated: "use new instead"})
+	f := old.Flag("f", "", nil)
+	old.AddCommand("sub", "", nil)
+	if e := p.Parse([]string{"old", "-f"}); e != nil {
+		t.Error(e)
+		return
+	}
+	if !*f || len(warnings) != 1 || warnings[0] != "command 'tool old' is deprecated: use new instead" {
+		t.Error("failed to warn deprecated command")
+		return
+	}
+	if !strings.Contains(old.FormatHelp(), "sub") {
+		t.Error("nested command sharing config should not be deprecated")
+		return
+	}
+
+	p = NewParser("tool", "", &ParserConfig{Stderr: &errOut})
+	p.String("", "mode", nil)
+	fast := p.Flag("", "fast", nil)
+	p.Flag("", "quick", &Option{Deprecated: "use --fast instead", ReplacedBy: "fast"})
+	if e := p.Parse([]string{"--quick"}); e != nil || !*fast {
+		t.Error("failed to forward flag to replacement")
+		return
+	}
+	for _, c := range []struct {
+		a       *arg
+		message string
+	}{
+		{newArg("", "a", new(string), &Option{Deprecated: "no more a", ReplacedBy: "b"}), "replacement 'b' is not found"},
+		{newArg("", "b", new(bool), &Option{Deprecated: "no more b", ReplacedBy: "mode"}), "flag replaced by non-flag 'mode'"},
+		{newArg("", "c", new(string), &Option{Deprecated: "no more c", ReplacedBy: "fast"}), "non-flag replaced by flag 'fast'"},
+	} {
+		if e := p.registerArgument(c.a); e == nil || e.Error() != c.message {
+			t.Error("failed to check replacement")
+			return
+		}
+	}
+}
+
+func TestAliases(t *testing.T) {
+	p := NewParser("tool", "", nil)
+	ttl := p.Int("t", "time-to-live", &Option{Aliases: []string{"expire"}, HiddenAliases: []string{"ttl"}})
+	for _, args := range [][]string{{"--time-to-live", "1"}, {"-t", "2"}, {"--expire", "3"}, {"--ttl", "4"}} {
+		if e := p.Parse(args); e != nil {
+			t.Error(e)
+			return
+		}
+	}
+	if *ttl != 4 {
+		t.Error("failed to parse aliases")
+		return
+	}
+	help := p.FormatHelp()
+	if !strings.Contains(help, "--time-to-live TIME-TO-LIVE, -t TIME-TO-LIVE, --expire TIME-TO-LIVE") ||
+		strings.Contains(help, "--ttl") || strings.Count(help, "[--time-to-live TIME-TO-LIVE]") != 1 {
+		t.Error("failed to show aliases in help")
+		return
+	}
+	completion := p.FormatCompletionScript()
+	if !strings.Contains(completion, "--expire") || strings.Contains(completion, "--ttl") {
+		t.Error("hidden alias should not be completed")
+		return
+	}
+	if e := p.Parse([]string{"--expire"}); e == nil || e.Error() != "argument --time-to-live/-t/--expire expect argument" {
+		t.Error("failed to show aliases in error")
+		return
+	}
+	if e := p.registerArgument(newArg("", "ttl", new(string), nil)); e == nil || e.Error() != "conflict entry for '--ttl', say: ''" {
+		t.Error("failed to check conflict for alias")
+		return
+	}
+	for _, opts := range []*Option{{Aliases: []string{"-x"}}, {Aliases: []string{"a b"}}, {HiddenAliases: []string{"x"}}, {Aliases: []string{"y"}, Positional: true},
+		{Aliases: []string{"zz", "zz"}}, {Aliases: []string{"zz"}, HiddenAliases: []string{"zz"}}} {
+		if e := p.registerArgument(newArg("", "x", new(string), opts)); e == nil {
+			t.Error("failed to check alias")
+			return
+		}
+	}
+}
+
+func TestPinnedWidth(t *testing.T) {
+	p := NewParser("t", "", &ParserConfig{Width: 40})
+	p.String("", "name", &Option{Help: strings.Repeat("word ", 20)})
+	sub := p.AddCommand("sub", "", &ParserConfig{})
+	for _, line := range strings.Split(p.FormatHelp(), "\n") {
+		if displayWidth(line) > 40 {
+			t.Error("help should be wrapped at pinned width")
+			return
+		}
+	}
+	if sub.config.Width != 40 {
+		t.Error("sub command should inherit width")
+		return
+	}
+}
+
+func TestExamples(t *testing.T) {
+	p := NewParser("tool", "", &ParserConfig{
+		Width:  40,
+		EpiLog: "first paragraph\n\nsecond paragraph",
+		Examples: []Example{
+			{Command: "tool --name a-very-long-name-which-should-never-be-wrapped-at-all", Description: "greet with a very long name which is wrapped"},
+			{Command: "tool"},
+		},
+	})
+	p.String("", "name", nil)
+	sub := p.AddCommand("sub", "", nil)
+	help := p.FormatHelp()
+	expect := `
+
+examples:
+  tool --name a-very-long-name-which-should-never-be-wrapped-at-all
+    greet with a very long name which is
+    wrapped
+  tool
+
+first paragraph
+
+second paragraph`
+	if !strings.HasSuffix(help, expect) {
+		t.Error("failed to show examples & epilog paragraphs")
+		return
+	}
+	if strings.Contains(sub.FormatHelp(), "examples:") {
+		t.Error("sub command sharing config should not show examples")
+		return
+	}
+	if !strings.Contains(p.FormatMarkdown(), "## Examples\n\ngreet with a very long name which is wrapped\n\n```\ntool --name") {
+		t.Error("failed to show examples in markdown")
+		return
+	}
+	if len(p.Describe().Examples) != 2 {
+		t.Error("failed to describe examples")
+		return
+	}
+}
+
+func TestLimits(t *testing.T) {
+	parser := NewParser("tool", "", &ParserConfig{WithHint: true})
+	port := parser.Int("p", "port", &Option{Min: Bound(1), Max: Bound(65535)})
+	ratio := parser.Float("", "ratio", &Option{Max: Bound(0.5)})
+	name := parser.String("", "name", &Option{Pattern: "^[a-z]+$"})
+	hosts := parser.Strings("", "hosts", &Option{MinLen: 2, MaxLen: 3})
+	if e := parser.Parse([]string{"-p", "80", "--ratio", "0.25", "--name", "web", "--hosts", "a", "--hosts", "b"}); e != nil {
+		t.Error(e.Error())
+		return
+	}
+	if *port != 80 || *ratio != 0.25 || *name != "web" || len(*hosts) != 2 {
+		t.Error("failed to bind values in limits")
+		return
+	}
+	for _, c := range []struct {
+		args    []string
+		message string
+	}{
+		{[]string{"-p", "0"}, "value 0 is less than 1"},
+		{[]string{"-p", "65536"}, "value 65536 is greater than 65535"},
+		{[]string{"--ratio", "0.75"}, "value 0.75 is greater than 0.5"},
+		{[]string{"--name", "Web"}, "value Web doesn't match pattern ^[a-z]+$"},
+		{[]string{"--hosts", "a"}, "argument hosts expect at least 2 values, got 1"},
+		{[]string{"--hosts", "a", "b", "c", "d"}, "argument hosts expect at most 3 values, got 4"},
+	} {
+		parser = NewParser("tool", "", nil)
+		parser.Int("p", "port", &Option{Min: Bound(1), Max: Bound(65535)})
+		parser.Float("", "ratio", &Option{Max: Bound(0.5)})
+		parser.String("", "name", &Option{Pattern: "^[a-z]+$"})
+		parser.Strings("", "hosts", &Option{MinLen: 2, MaxLen: 3})
+		e := parser.Parse(c.args)
+		var invalid InvalidValueError
+		if e == nil || !errors.As(e, &invalid) || e.Error() != c.message {
+			t.Error("failed to check limits: " + c.message)
+			return
+		}
+	}
+	hint := (&arg{full: "port", Option: Option{Min: Bound(1), Max: Bound(65535)}}).formatHelpWithExtraInfo()
+	if hint != "(range: 1 ~ 65535)" {
+		t.Error("failed to hint range: " + hint)
+		return
+	}
+	hint = (&arg{full: "hosts", Option: Option{Pattern: "^a", MinLen: 2}}).formatHelpWithExtraInfo()
+	if hint != "(pattern: ^a, at least 2 values)" {
+		t.Error("failed to hint pattern & count: " + hint)
+		return
+	}
+}
+
+func TestValidator(t *testing.T) {
+	var order []string
+	parser := NewParser("tool", "", nil)
+	verbose := parser.Flag("v", "verbose", &Option{Inheritable: true})
+	parser.AddValidator(func(p *Parser) error {
+		order = append(order, p.Name())
+		if *verbose && p.IsSet("quiet") {
+			return fmt.Errorf("--verbose conflicts with --quiet")
+		}
+		return nil
+	})
+	parser.Flag("q", "quiet", nil)
+	deploy := parser.AddCommand("deploy", "", nil)
+	minReplicas := deploy.Int("", "min-replicas", &Option{Default: "1"})
+	replicas := deploy.Int("", "replicas", &Option{Required: true})
+	deploy.AddValidator(func(p *Parser) error {
+		order = append(order, p.Name())
+		if *replicas < *minReplicas {
+			return fmt.Errorf("--replicas must be >= --min-replicas")
+		}
+		return nil
+	})
+
+	if e := parser.Parse([]string{"deploy", "--replicas", "2", "-v"}); e != nil {
+		t.Error(e.Error())
+		return
+	}
+	if strings.Join(order, ",") != "deploy,tool" {
+		t.Error("validators should run from inner to outer")
+		return
+	}
+	e := parser.Parse([]string{"deploy", "--replicas", "0"})
+	var invalid ValidationError
+	if !errors.As(e, &invalid) || invalid.Path != "tool deploy" || e.Error() != "--replicas must be >= --min-replicas" {
+		t.Error("failed to wrap validation error with path")
+		return
+	}
+	parser = NewParser("tool", "", nil)
+	level := parser.Int("", "level", &Option{Default: "3"})
+	var seen int
+	parser.AddValidator(func(p *Parser) error {
+		seen = *level
+		return nil
+	})
+	parser.AddCommand("sync", "", nil).Flag("x", "", nil)
+	if e := parser.Parse([]string{"sync", "-x"}); e != nil || seen != 3 {
+		t.Error("parent validator should see default value")
+		return
+	}
+	parser = NewParser("tool", "", nil)
+	parser.Int("", "start", nil)
+	parser.AddValidator(func(p *Parser) error {
+		return fmt.Errorf("invalid")
+	})
+	parser.AddValidator(func(p *Parser) error {
+		t.Error("validators should stop at the first error")
+		return nil
+	})
+	e = parser.Parse([]string{"--start", "1"})
+	if !errors.As(e, &invalid) || invalid.Path != "tool" || invalid.Code() != CodeValidation || parser.Invoked {
+		t.Error("failed to return validation error")
+		return
+	}
+}
+
+func TestPositionalsAfterMulti(t *testing.T) {
+	p := NewParser("cp", "", nil)
+	force := p.Flag("f", "force", nil)
+	src := p.Strings("", "src", &Option{Positional: true, Required: true})
+	dst := p.String("", "dst", &Option{Positional: true, Required: true})
+	if e := p.Parse([]string{"a", "b", "-f", "c"}); e != nil {
+		t.Error(e.Error())
+		return
+	}
+	if strings.Join(*src, ",") != "a,b" || *dst != "c" || !*force {
+		t.Error("failed to leave input for positional after multi positional")
+		return
+	}
+	if !strings.HasPrefix(p.FormatHelp(), "usage: cp [--help] [--force] SRC [SRC ...] DST") {
+		t.Error("failed to show usage of positionals")
+		return
+	}
+	p = NewParser("cp", "", nil)
+	p.Strings("", "src", &Option{Positional: true, Required: true})
+	p.String("", "dst", &Option{Positional: true, Required: true})
+	e := p.Parse([]string{"a"})
+	var required RequiredError
+	if !errors.As(e, &required) || required.Argument != "DST" {
+		t.Error("failed to report missing positional")
+		return
+	}
+
+	p = NewParser("", "", nil)
+	a := p.String("", "a", &Option{Positional: true, Required: true})
+	b := p.String("", "b", &Option{Positional: true})
+	c := p.String("", "c", &Option{Positional: true, Required: true})
+	if e := p.Parse([]string{"x", "y"}); e != nil {
+		t.Error(e.Error())
+		return
+	}
+	if *a != "x" || *b != "" || *c != "y" || p.IsSet("b") {
+		t.Error("optional positional should leave input for required one")
+		return
+	}
+	p = NewParser("", "", nil)
+	a = p.String("", "a", &Option{Positional: true, Required: true})
+	b = p.String("", "b", &Option{Positional: true})
+	c = p.String("", "c", &Option{Positional: true, Required: true})
+	if e := p.Parse([]string{"x", "y", "z"}); e != nil {
+		t.Error(e.Error())
+		return
+	}
+	if *a != "x" || *b != "y" || *c != "z" {
+		t.Error("failed to fill optional positional")
+		return
+	}
+	e = p.Parse([]string{"x", "y", "z", "w"})
+	var unknown UnknownArgumentError
+	if !errors.As(e, &unknown) || unknown.Token != "w" {
+		t.Error("surplus input should be unrecognized")
+		return
+	}
+
+	p = NewParser("", "", nil)
+	head := p.String("", "head", &Option{Positional: true, Required: true})
+	middle := p.Strings("", "middle", &Option{Positional: true})
+	tail := p.Strings("", "tail", &Option{Positional: true, Required: true})
+	if e := p.Parse([]string{"1", "2", "3", "--", "4"}); e != nil {
+		t.Error(e.Error())
+		return
+	}
+	if *head != "1" || strings.Join(*middle, ",") != "2,3" || strings.Join(*tail, ",") != "4" {
+		t.Error("failed to assign positionals with extra input")
+		return
+	}
+}
@@ -0,0 +1,145 @@
+package argparse
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// PathCheck decides how File, Path & Paths arguments are checked, combined by '|', like PathMustExist | PathIsDir
+type PathCheck int
+
+const (
+	PathMustExist    PathCheck = 1 << iota // the path must exist
+	PathMustNotExist                       // the path must not exist, like output without overwrite
+	PathIsDir                              // the path must be a directory if it exists
+	PathIsRegular                          // the path must be a regular file if it exists
+	PathReadable                           // the path must be readable if it exists
+	PathWritable                           // the path must be writable, or can be created. File is opened to write
+	PathExpandGlob                         // expand glob pattern like '*.go', Path takes it if exactly one path matches
+	PathAllowStdio                         // '-' means stdin, or stdout for writable File
+)
+
+// validatePathCheck check Option.PathCheck setting for the argument type
+func (a *arg) validatePathCheck() error {
+	if a.PathCheck != 0 && !a.isPath {
+		return fmt.Errorf("path check for non-path")
+	}
+	c := a.PathCheck
+	if c&PathMustExist != 0 && c&PathMustNotExist != 0 {
+		return fmt.Errorf("path must exist & must not exist")
+	}
+	if c&PathIsDir != 0 && c&PathIsRegular != 0 {
+		return fmt.Errorf("path is dir & regular file")
+	}
+	if _, isFile := a.target.(**os.File); isFile && c&PathIsDir != 0 {
+		return fmt.Errorf("file is dir")
+	}
+	return nil
+}
+
+// resolvePaths expand & clean user input paths, then check them by Option.PathCheck
+func (a *arg) resolvePaths(values []string) ([]interface{}, error) {
+	var result []interface{}
+	for _, raw := range values {
+		if raw == "-" && a.PathCheck&PathAllowStdio != 0 {
+			result = append(result, raw)
+			continue
+		}
+		matches := []string{raw}
+		if a.PathCheck&PathExpandGlob != 0 {
+			expanded, e := filepath.Glob(raw)
+			if e != nil {
+				return nil, InvalidValueError{Argument: a.getIdentifier(), Token: raw, Err: fmt.Errorf("invalid glob pattern %s", raw)}
+			}
+			if len(expanded) > 0 {
+				matches = expanded
+			}
+			if !a.multi && len(matches) > 1 {
+				return nil, InvalidValueError{Argument: a.getIdentifier(), Token: raw,
+					Err: fmt.Errorf("pattern %s matches %d paths", raw, len(matches))}
+			}
+		}
+		for _, m := range matches {
+			path := filepath.Clean(m)
+			if e := a.checkPath(path); e != nil {
+				return nil, InvalidValueError{Argument: a.getIdentifier(), Token: raw, Err: e}
+			}
+			result = append(result, path)
+		}
+	}
+	return result, nil
+}
+
+// checkPath check the path by Option.PathCheck
+func (a *arg) checkPath(path string) error {
+	c := a.PathCheck
+	info, e := os.Stat(path)
+	if e != nil && !os.IsNotExist(e) {
+		return e
+	}
+	exists := e == nil
+	if c&PathMustExist != 0 && !exists {
+		return fmt.Errorf("%s does not exist", path)
+	}
+	if c&PathMustNotExist != 0 && exists {
+		return fmt.Errorf("%s already exists", path)
+	}
+	if !exists {
+		if c&PathWritable != 0 && !writable(filepath.Dir(path)) {
+			return fmt.Errorf("%s can't be created", path)
+		}
+		return nil
+	}
+	if c&PathIsDir != 0 && !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", path)
+	}
+	if c&PathIsRegular != 0 && !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", path)
+	}
+	if c&PathReadable != 0 {
+		f, e := os.Open(path)
+		if e != nil {
+			return fmt.Errorf("%s is not readable", path)
+		}
+		f.Close()
+	}
+	if c&PathWritable != 0 && !writable(path) {
+		return fmt.Errorf("%s is not writable", path)
+	}
+	return nil
+}
+
+// openFile open the checked path for File argument, to write if PathWritable is set, or to read
+func (a *arg) openFile(path string) (*os.File, error) {
+	writable := a.PathCheck&PathWritable != 0
+	if path == "-" && a.PathCheck&PathAllowStdio != 0 {
+		if writable {
+			return os.Stdout, nil
+		}
+		return os.Stdin, nil
+	}
+	if writable {
+		return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	}
+	return os.Open(path)
+}
+
+// bindFile open the path checked during parsing & bind the file, the file bound before is closed
+func (a *arg) bindFile() error {
+	if a.filePath == "" {
+		return nil
+	}
+	path := a.filePath
+	a.filePath = ""
+	f, e := a.openFile(path)
+	if e != nil {
+		return InvalidValueError{Argument: a.getIdentifier(), Token: path, Err: e}
+	}
+	target := a.target.(**os.File)
+	if previous := *target; previous != nil && previous != os.Stdin && previous != os.Stdout {
+		previous.Close()
+	}
+	*target = f
+	return nil
+}
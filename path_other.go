@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !freebsd
+// +build !linux,!darwin,!freebsd
+
+package argparse
+
+import "os"
+
+// writable tells whether the path can be written by its permission bits, there is no access check on the platform
+func writable(path string) bool {
+	info, e := os.Stat(path)
+	return e == nil && info.Mode().Perm()&0200 != 0
+}
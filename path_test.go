@@ -0,0 +1,181 @@
+package argparse
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPathArguments(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.txt")
+	if e := os.WriteFile(file, []byte("content"), 0644); e != nil {
+		t.Error(e.Error())
+		return
+	}
+	if e := os.WriteFile(filepath.Join(dir, "b.txt"), nil, 0644); e != nil {
+		t.Error(e.Error())
+		return
+	}
+
+	p := NewParser("tool", "", nil)
+	input := p.File("i", "input", nil)
+	output := p.File("o", "output", &Option{PathCheck: PathWritable | PathAllowStdio})
+	workdir := p.Path("", "workdir", &Option{PathCheck: PathMustExist | PathIsDir})
+	sources := p.Paths("", "sources", &Option{PathCheck: PathExpandGlob | PathIsRegular})
+	if e := p.Parse([]string{"-i", file, "-o", "-", "--workdir", dir + "/./", "--sources", filepath.Join(dir, "*.txt")}); e != nil {
+		t.Error(e.Error())
+		return
+	}
+	defer (*input).Close()
+	content := make([]byte, 7)
+	if n, _ := (*input).Read(content); n != 7 || string(content) != "content" {
+		t.Error("failed to open file to read")
+		return
+	}
+	if *output != os.Stdout {
+		t.Error("'-' should be stdout for writable file")
+		return
+	}
+	if *workdir != dir {
+		t.Error("failed to clean path")
+		return
+	}
+	if len(*sources) != 2 {
+		t.Error("failed to expand glob")
+		return
+	}
+
+	p = NewParser("tool", "", nil)
+	output = p.File("o", "output", &Option{PathCheck: PathWritable | PathMustNotExist})
+	target := filepath.Join(dir, "c.txt")
+	if e := p.Parse([]string{"-o", target}); e != nil {
+		t.Error(e.Error())
+		return
+	}
+	(*output).WriteString("written")
+	(*output).Close()
+	if written, _ := os.ReadFile(target); string(written) != "written" {
+		t.Error("failed to open file to write")
+		return
+	}
+
+	for _, args := range [][]string{{"-o", file, "--bogus"}, {"-o", file, "-h"}} {
+		p = NewParser("tool", "", &ParserConfig{Stdout: &bytes.Buffer{}})
+		output = p.File("o", "output", &Option{PathCheck: PathWritable})
+		if e := p.Parse(args); e == nil || *output != nil {
+			t.Error("file should not be opened when parsing fails")
+			return
+		}
+		if kept, _ := os.ReadFile(file); string(kept) != "content" {
+			t.Error("file should not be truncated when parsing fails")
+			return
+		}
+	}
+	p = NewParser("tool", "", nil)
+	output = p.File("o", "output", &Option{PathCheck: PathWritable})
+	p.AddValidator(func(parser *Parser) error {
+		if *output != nil {
+			t.Error("file should not be opened before validators pass")
+		}
+		return fmt.Errorf("invalid")
+	})
+	if e := p.Parse([]string{"-o", file}); e == nil || *output != nil {
+		t.Error("file should not be opened when validation fails")
+		return
+	}
+	if kept, _ := os.ReadFile(file); string(kept) != "content" {
+		t.Error("file should not be truncated when validation fails")
+		return
+	}
+
+	p = NewParser("tool", "", nil)
+	output = p.File("o", "output", &Option{PathCheck: PathWritable})
+	first, last := filepath.Join(dir, "first.txt"), filepath.Join(dir, "last.txt")
+	if e := p.Parse([]string{"-o", first, "-o", last}); e != nil {
+		t.Error(e.Error())
+		return
+	}
+	(*output).Close()
+	if _, e := os.Stat(first); !os.IsNotExist(e) || (*output).Name() != last {
+		t.Error("only the last given file should be opened")
+		return
+	}
+	os.Remove(last)
+
+	p = NewParser("tool", "", nil)
+	p.Paths("", "in", &Option{PathCheck: PathExpandGlob, Pattern: `\.go$`})
+	var invalid InvalidValueError
+	if e := p.Parse([]string{"--in", filepath.Join(dir, "*")}); !errors.As(e, &invalid) ||
+		!strings.Contains(invalid.Error(), "doesn't match pattern") {
+		t.Error("expanded paths should be checked by pattern")
+		return
+	}
+
+	for _, c := range []struct {
+		check   PathCheck
+		input   string
+		message string
+	}{
+		{PathMustExist, filepath.Join(dir, "none"), "does not exist"},
+		{PathMustNotExist, file, "already exists"},
+		{PathIsDir, file, "is not a directory"},
+		{PathIsRegular, dir, "is not a regular file"},
+		{PathExpandGlob, filepath.Join(dir, "*.txt"), "matches 3 paths"},
+		{PathWritable, filepath.Join(dir, "none", "x"), "can't be created"},
+	} {
+		p = NewParser("tool", "", nil)
+		p.Path("", "path", &Option{PathCheck: c.check})
+		e := p.Parse([]string{"--path", c.input})
+		var invalid InvalidValueError
+		if !errors.As(e, &invalid) || !strings.Contains(e.Error(), c.message) {
+			t.Error("failed to check path: " + c.message)
+			return
+		}
+	}
+	p = NewParser("tool", "", nil)
+	p.File("", "input", nil)
+	if e := p.Parse([]string{"--input", filepath.Join(dir, "none")}); e == nil {
+		t.Error("file to read should exist")
+		return
+	}
+}
+
+func TestValidatePathCheck(t *testing.T) {
+	var s string
+	var f *os.File
+	for _, c := range []struct {
+		a       *arg
+		message string
+	}{
+		{&arg{full: "a", target: &s, Option: Option{PathCheck: PathMustExist}}, "path check for non-path"},
+		{&arg{full: "a", target: &s, isPath: true, Option: Option{PathCheck: PathMustExist | PathMustNotExist}}, "path must exist & must not exist"},
+		{&arg{full: "a", target: &s, isPath: true, Option: Option{PathCheck: PathIsDir | PathIsRegular}}, "path is dir & regular file"},
+		{&arg{full: "a", target: &f, isPath: true, Option: Option{PathCheck: PathIsDir}}, "file is dir"},
+	} {
+		if e := c.a.validate(); e == nil || e.Error() != c.message {
+			t.Error("failed to validate path check: " + c.message)
+			return
+		}
+	}
+}
+
+func TestPathCompletion(t *testing.T) {
+	p := NewParser("tool", "", nil)
+	p.File("i", "input", nil)
+	p.Path("", "dir", &Option{PathCheck: PathIsDir})
+	if !strings.Contains(p.formatBashCompletionScript(), "--input|-i) COMPREPLY=($(compgen -f -- $cur))") ||
+		!strings.Contains(p.formatBashCompletionScript(), "--dir) COMPREPLY=($(compgen -d -- $cur))") {
+		t.Error("failed to complete paths in bash")
+		return
+	}
+	script := p.formatZshCompletionScript()
+	if !strings.Contains(script, `"--input:INPUT:_files"`) || !strings.Contains(script, `"--dir:DIR:_files -/"`) {
+		t.Error("failed to complete paths in zsh")
+		return
+	}
+}
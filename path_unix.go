@@ -0,0 +1,13 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package argparse
+
+import "syscall"
+
+const accessWrite = 0x2 // W_OK of access(2)
+
+// writable tells whether the path can be written, or files can be created in it for a directory
+func writable(path string) bool {
+	return syscall.Access(path, accessWrite) == nil
+}
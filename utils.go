@@ -2,47 +2,188 @@ package argparse
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
+	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
-func decideTerminalWidth() int {
-	// decide terminal width
-	cmd := exec.Command("stty", "size")
-	cmd.Stdin = os.Stdin // this is important
-	result, e := cmd.Output()
-	if e != nil {
-		result = []byte("0 80")
+// minContentWidth is the minimum width for help content, even if the terminal is too narrow
+const minContentWidth = 20
+
+// decideTerminalWidth decide help message width by pinned width, COLUMNS env,
+// terminal size of the output & default width in order
+func decideTerminalWidth(out io.Writer, pinned, defaultWidth int) int {
+	if pinned > 0 {
+		return pinned
+	}
+	if w, e := strconv.Atoi(os.Getenv("COLUMNS")); e == nil && w > 0 {
+		return w
+	}
+	if f, ok := out.(interface{ Fd() uintptr }); ok {
+		if w := queryTerminalWidth(f.Fd()); w > 0 {
+			return w
+		}
+	}
+	if defaultWidth > 0 {
+		return defaultWidth
+	}
+	return 80
+}
+
+// wideRanges are east asian wide & fullwidth characters, which take 2 columns in terminal
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F}, {0x2E80, 0x303E}, {0x3041, 0x33FF}, {0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF}, {0xA000, 0xA4CF}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF},
+	{0xFE30, 0xFE4F}, {0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x1F300, 0x1F64F},
+	{0x1F900, 0x1F9FF}, {0x20000, 0x2FFFD}, {0x30000, 0x3FFFD},
+}
+
+// runeWidth returns columns the rune takes in terminal
+func runeWidth(r rune) int {
+	if r < 0x20 || r == 0x7F || unicode.Is(unicode.Mn, r) { // control & combining characters
+		return 0
+	}
+	for _, rg := range wideRanges {
+		if r >= rg[0] && r <= rg[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// escapeLength returns the byte length of color escape sequence at the start of content, like \033[01;32m
+func escapeLength(content string) int {
+	if !strings.HasPrefix(content, "\033[") {
+		return 0
 	}
-	width := 80
-	parse := strings.Split(strings.TrimRight(string(result), "\n"), " ")
-	if w, e := strconv.Atoi(parse[1]); e == nil {
-		width = w
+	for i := 2; i < len(content); i++ {
+		if content[i] >= '@' && content[i] <= '~' {
+			return i + 1
+		}
+	}
+	return len(content)
+}
+
+// displayWidth returns columns the content takes in terminal, color escapes excluded
+func displayWidth(content string) int {
+	width := 0
+	for i := 0; i < len(content); {
+		if l := escapeLength(content[i:]); l > 0 {
+			i += l
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(content[i:])
+		width += runeWidth(r)
+		i += size
 	}
 	return width
 }
 
+// splitWidth split content at the position where its display width reaches width,
+// characters & color escapes are not broken
+func splitWidth(content string, width int) (head, tail string) {
+	current := 0
+	for i := 0; i < len(content); {
+		if l := escapeLength(content[i:]); l > 0 {
+			i += l
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(content[i:])
+		w := runeWidth(r)
+		if current+w > width && current > 0 {
+			return content[:i], content[i:]
+		}
+		current += w
+		i += size
+	}
+	return content, ""
+}
+
+// listMarker matches leading indent & list marker of a line, like '  - ', '1. '
+var listMarker = regexp.MustCompile(`^\s*(([-*+•]|\d+[.)])\s+)?`)
+
+// wrapText break text into lines at word boundaries, first line within firstWidth, the others within width.
+// explicit line breaks are kept, and lines broken from an indented line or a list item keep the indent
+func wrapText(text string, firstWidth, width int) []string {
+	var result []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t")
+		limit := width
+		if len(result) == 0 {
+			limit = firstWidth
+		}
+		if displayWidth(line) <= limit {
+			result = append(result, line)
+			continue
+		}
+		prefix := listMarker.FindString(line)
+		if prefixWidth := displayWidth(prefix); prefixWidth >= limit || prefixWidth >= width {
+			prefix = "" // indent takes the whole line, not kept
+		}
+		indent := strings.Repeat(" ", displayWidth(prefix))
+		current := prefix
+		currentWidth := displayWidth(prefix)
+		for _, word := range strings.Fields(line[len(prefix):]) {
+			wordWidth := displayWidth(word)
+			if currentWidth > len(indent) && currentWidth+1+wordWidth > limit {
+				result = append(result, current) // no more space for the word, start a new line
+				limit = width
+				current, currentWidth = indent, len(indent)
+			}
+			if currentWidth > len(indent) {
+				current += " "
+				currentWidth += 1
+			}
+			for word != "" && currentWidth+wordWidth > limit { // the word is too long to fit in a line
+				head, tail := splitWidth(word, limit-currentWidth)
+				result = append(result, current+head)
+				limit = width
+				current, currentWidth = indent, len(indent)
+				word, wordWidth = tail, displayWidth(tail)
+			}
+			current += word
+			currentWidth += wordWidth
+		}
+		result = append(result, current)
+	}
+	return result
+}
+
 func formatHelpRow(head, content string, bareHeadLength, maxHeadLength, terminalWidth int, withBreak bool) string {
-	content = strings.Replace(content, "\n", "", -1)
 	result := fmt.Sprintf("  %s ", head)
 	headLeftPadding := maxHeadLength - bareHeadLength - 3
 	if headLeftPadding > 0 { // fill left padding
 		result += strings.Repeat(" ", headLeftPadding)
 	}
 	contentPadding := strings.Repeat(" ", maxHeadLength)
+	contentWidth := terminalWidth - maxHeadLength
+	if contentWidth < minContentWidth {
+		contentWidth = minContentWidth
+	}
+	if content == "" {
+		return result
+	}
 	var rows []string
-	if withBreak && headLeftPadding < 0 {
-		rows = append(rows, result, contentPadding+content)
-	} else {
-		rows = append(rows, result+content)
-	}
-	for len(rows[len(rows)-1]) > terminalWidth { // break into lines
-		lastIndex := len(rows) - 1
-		lastOne := rows[lastIndex]
-		rows[lastIndex] = rows[lastIndex][0:terminalWidth]
-		rows = append(rows, contentPadding+lastOne[terminalWidth:])
+	firstWidth := contentWidth
+	if withBreak && headLeftPadding < 0 { // content starts at next line
+		rows = append(rows, result)
+		result = contentPadding
+	} else if headLeftPadding < 0 { // content follows the long header
+		firstWidth = terminalWidth - bareHeadLength - 3
+		if firstWidth < minContentWidth {
+			firstWidth = minContentWidth
+		}
+	}
+	for i, line := range wrapText(content, firstWidth, contentWidth) {
+		if i == 0 {
+			rows = append(rows, result+line)
+		} else {
+			rows = append(rows, strings.TrimRight(contentPadding+line, " "))
+		}
 	}
 	return strings.Join(rows, "\n")
 }
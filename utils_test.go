@@ -1,12 +1,14 @@
 package argparse
 
 import (
+	"bytes"
+	"os"
 	"strings"
 	"testing"
 )
 
 func TestFormatHelpRow(t *testing.T) {
-	width := decideTerminalWidth()
+	width := 80
 	header := "this is header"
 	if strings.Count(formatHelpRow(header, strings.Repeat("C", 50), len(header), 30, width, false),
 		"\n") > 0 {
@@ -39,3 +41,107 @@ func TestFormatHelpRow(t *testing.T) {
 		return
 	}
 }
+
+func TestDisplayWidth(t *testing.T) {
+	if displayWidth("abc") != 3 {
+		t.Error("ascii width error")
+		return
+	}
+	if displayWidth("中文") != 4 {
+		t.Error("wide character should take 2 columns")
+		return
+	}
+	if displayWidth(wrapperColor("abc", Color{Code: 32, Property: 1})) != 3 {
+		t.Error("color escape should be excluded")
+		return
+	}
+	if head, tail := splitWidth("中文字", 3); head != "中" || tail != "文字" {
+		t.Error("wide character should not be broken")
+		return
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	lines := wrapText("the quick brown fox jumps over the lazy dog", 15, 15)
+	if len(lines) != 3 || lines[0] != "the quick brown" || lines[2] != "the lazy dog" {
+		t.Error("should break at word boundaries")
+		return
+	}
+	for _, l := range lines {
+		if displayWidth(l) > 15 {
+			t.Error("line is too long")
+			return
+		}
+	}
+	lines = wrapText("first paragraph\n\nsecond", 40, 40)
+	if len(lines) != 3 || lines[1] != "" {
+		t.Error("paragraph break should be kept")
+		return
+	}
+	lines = wrapText("items:\n  - alpha beta gamma delta", 16, 16)
+	if len(lines) != 3 || lines[1] != "  - alpha beta" || lines[2] != "    gamma delta" {
+		t.Error("indented list should keep indent")
+		return
+	}
+	lines = wrapText("-v enables verbose mode", 12, 12)
+	if len(lines) != 2 || lines[0] != "-v enables" {
+		t.Error("dash without space is not a list marker")
+		return
+	}
+	lines = wrapText("中文中文中文中文", 6, 6)
+	if len(lines) != 3 || lines[0] != "中文中" {
+		t.Error("wide characters should be split by display width")
+		return
+	}
+	lines = wrapText("go "+wrapperColor("colorful", Color{Code: 32, Property: 1})+" text", 11, 11)
+	if len(lines) != 2 || displayWidth(lines[0]) != 11 {
+		t.Error("color escape should not count in width")
+		return
+	}
+	lines = wrapText(strings.Repeat(" ", 50)+"tool --name alpha beta", 20, 20)
+	if len(lines) != 2 || lines[0] != "tool --name alpha" || lines[1] != "beta" {
+		t.Error("indent wider than the line should not be kept")
+		return
+	}
+	p := NewParser("tool", "", &ParserConfig{Width: 60})
+	p.String("", "name", &Option{Help: "example:\n" + strings.Repeat(" ", 50) + "tool --name alpha beta gamma"})
+	if !strings.Contains(p.FormatHelp(), "tool --name alpha") {
+		t.Error("failed to wrap deep indented help")
+		return
+	}
+	if row := formatHelpRow("header", "a b\nc", 6, 10, 80, false); strings.Count(row, "\n") != 1 ||
+		!strings.HasSuffix(row, "\n          c") {
+		t.Error("explicit newline should be kept with padding")
+		return
+	}
+}
+
+func TestDecideTerminalWidth(t *testing.T) {
+	columns, exist := os.LookupEnv("COLUMNS")
+	defer func() {
+		if exist {
+			os.Setenv("COLUMNS", columns)
+		} else {
+			os.Unsetenv("COLUMNS")
+		}
+	}()
+	os.Setenv("COLUMNS", "100")
+	if decideTerminalWidth(&bytes.Buffer{}, 60, 0) != 60 {
+		t.Error("pinned width should be used first")
+		return
+	}
+	if decideTerminalWidth(&bytes.Buffer{}, 0, 0) != 100 {
+		t.Error("COLUMNS should be used")
+		return
+	}
+	os.Setenv("COLUMNS", "wide")
+	if decideTerminalWidth(&bytes.Buffer{}, 0, 120) != 120 {
+		t.Error("default width should be used for non-terminal output")
+		return
+	}
+	os.Unsetenv("COLUMNS")
+	if decideTerminalWidth(&bytes.Buffer{}, 0, 0) != 80 {
+		t.Error("width should fall back to 80")
+		return
+	}
+}
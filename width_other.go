@@ -0,0 +1,24 @@
+//go:build !linux && !darwin && !freebsd
+// +build !linux,!darwin,!freebsd
+
+package argparse
+
+import (
+	"io"
+	"os"
+)
+
+// queryTerminalWidth is not supported on the platform, the width is decided by COLUMNS env or default
+func queryTerminalWidth(fd uintptr) int {
+	return 0
+}
+
+// isTerminal tells whether the output is a character device, which is usually a terminal
+func isTerminal(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	stat, e := f.Stat()
+	return e == nil && stat.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,35 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package argparse
+
+import (
+	"io"
+	"syscall"
+	"unsafe"
+)
+
+// winSize query the window size of the terminal by ioctl, ok is false if fd is not a terminal
+func winSize(fd uintptr) (cols int, ok bool) {
+	var size struct{ rows, cols, xPixel, yPixel uint16 }
+	if _, _, e := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&size))); e != 0 {
+		return 0, false
+	}
+	return int(size.cols), true
+}
+
+// queryTerminalWidth query columns of the terminal by ioctl, 0 if fd is not a terminal
+func queryTerminalWidth(fd uintptr) int {
+	cols, _ := winSize(fd)
+	return cols
+}
+
+// isTerminal tells whether the output is a terminal
+func isTerminal(out io.Writer) bool {
+	f, ok := out.(interface{ Fd() uintptr })
+	if !ok {
+		return false
+	}
+	_, ok = winSize(f.Fd())
+	return ok
+}